@@ -0,0 +1,173 @@
+package envvalidator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// BindStruct builds a Validator from the struct tags on target, validates env
+// against it, and assigns the parsed values into target's fields. target must
+// be a non-nil pointer to a struct.
+//
+// The following tags are recognized on exported fields:
+//
+//	env:"PORT"            the environment variable key (required to bind the field)
+//	kind:"integer"        the Kind; inferred from the Go type when omitted
+//	default:"8080"        the Default value
+//	required:"true"       marks the field as Required
+//	description:"..."     the Description used in error messages
+//
+// Unexported fields and fields without an env tag are skipped. Integer values
+// are assigned to any signed or unsigned integer field that can hold them,
+// floats to float32 or float64, durations to time.Duration, and string-valued
+// kinds to string fields.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port    int           `env:"PORT" default:"8080"`
+//	    DBURL   string        `env:"DATABASE_URL" kind:"url" required:"true"`
+//	    Timeout time.Duration `env:"TIMEOUT" default:"30s"`
+//	}
+//	var cfg Config
+//	err := envvalidator.BindStruct(context.Background(), env, &cfg)
+func BindStruct(ctx context.Context, env map[string]string, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("env-validator: BindStruct target must be a non-nil pointer to a struct, got %T", target)
+	}
+	sv := rv.Elem()
+	st := sv.Type()
+
+	var fields []Field
+	var indexes []int
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		key, ok := sf.Tag.Lookup("env")
+		if !ok || !sf.IsExported() {
+			continue
+		}
+		f := Field{
+			Key:         key,
+			Kind:        Kind(sf.Tag.Get("kind")),
+			Default:     sf.Tag.Get("default"),
+			Description: sf.Tag.Get("description"),
+		}
+		if req, ok := sf.Tag.Lookup("required"); ok {
+			b, err := strconv.ParseBool(req)
+			if err != nil {
+				return fmt.Errorf("env-validator: struct field %s: invalid required tag %q", sf.Name, req)
+			}
+			f.Required = b
+		}
+		if f.Kind == "" {
+			f.Kind = inferKind(sf.Type)
+		}
+		fields = append(fields, f)
+		indexes = append(indexes, i)
+	}
+
+	result, err := New(fields...).ValidateMap(ctx, env)
+	if err != nil {
+		return err
+	}
+	for i, f := range fields {
+		sf := st.Field(indexes[i])
+		if err := assignValue(sv.Field(indexes[i]), result.values[resultKey(f)]); err != nil {
+			return fmt.Errorf("env-validator: struct field %s: %w", sf.Name, err)
+		}
+	}
+	return nil
+}
+
+// Decode validates env against fields and, if validation succeeds, passes
+// the Result to assign and returns the value it builds. On failure it
+// returns the zero T and the validation error, and assign is not called.
+// Unlike BindStruct it needs no struct tags, so the typed accessors stay
+// next to the field declarations.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port    int
+//	    Timeout time.Duration
+//	}
+//	cfg, err := envvalidator.Decode(ctx, env, []envvalidator.Field{
+//	    {Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+//	    {Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "30s"},
+//	}, func(r *envvalidator.Result) Config {
+//	    return Config{Port: r.Port("PORT"), Timeout: r.Duration("TIMEOUT")}
+//	})
+func Decode[T any](ctx context.Context, env map[string]string, fields []Field, assign func(*Result) T) (T, error) {
+	result, err := New(fields...).ValidateMap(ctx, env)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return assign(result), nil
+}
+
+// inferKind picks a Kind from a struct field's Go type when no kind tag is
+// given. Unrecognized types fall back to KindString.
+func inferKind(t reflect.Type) Kind {
+	if t == durationType {
+		return KindDuration
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return KindInteger
+	case reflect.Float32, reflect.Float64:
+		return KindFloat
+	case reflect.Bool:
+		return KindBoolean
+	default:
+		return KindString
+	}
+}
+
+// assignValue stores a parsed value into dst, converting between compatible
+// numeric types and rejecting values that would overflow.
+func assignValue(dst reflect.Value, value any) error {
+	if d, ok := value.(time.Duration); ok && dst.Type() == durationType {
+		dst.SetInt(int64(d))
+		return nil
+	}
+	switch val := value.(type) {
+	case int64, int:
+		n := reflect.ValueOf(val).Int()
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if dst.OverflowInt(n) {
+				return fmt.Errorf("value %d overflows %s", n, dst.Type())
+			}
+			dst.SetInt(n)
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n < 0 || dst.OverflowUint(uint64(n)) {
+				return fmt.Errorf("value %d overflows %s", n, dst.Type())
+			}
+			dst.SetUint(uint64(n))
+			return nil
+		}
+	case float64:
+		if dst.Kind() == reflect.Float32 || dst.Kind() == reflect.Float64 {
+			if dst.OverflowFloat(val) {
+				return fmt.Errorf("value %g overflows %s", val, dst.Type())
+			}
+			dst.SetFloat(val)
+			return nil
+		}
+	}
+	src := reflect.ValueOf(value)
+	if !src.IsValid() || !src.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("cannot assign %T to %s", value, dst.Type())
+	}
+	dst.Set(src)
+	return nil
+}
@@ -0,0 +1,107 @@
+package envvalidator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+type bindConfig struct {
+	Port     int           `env:"PORT" default:"8080"`
+	Workers  uint8         `env:"WORKERS" kind:"integer" default:"4"`
+	Ratio    float32       `env:"RATIO" default:"0.25"`
+	Debug    bool          `env:"DEBUG" default:"false"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"30s"`
+	DBURL    string        `env:"DATABASE_URL" kind:"url" required:"true"`
+	Untagged string
+	hidden   string `env:"HIDDEN"`
+}
+
+func TestBindStruct_PopulatesFields(t *testing.T) {
+	var cfg bindConfig
+	err := envvalidator.BindStruct(context.Background(), map[string]string{
+		"PORT":         "9090",
+		"DEBUG":        "yes",
+		"TIMEOUT":      "2m",
+		"DATABASE_URL": "postgres://localhost/db",
+		"HIDDEN":       "ignored",
+	}, &cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Port != 9090 || cfg.Workers != 4 || cfg.Ratio != 0.25 || !cfg.Debug || cfg.Timeout != 2*time.Minute {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.DBURL != "postgres://localhost/db" {
+		t.Errorf("unexpected DBURL: %s", cfg.DBURL)
+	}
+	if cfg.hidden != "" || cfg.Untagged != "" {
+		t.Errorf("expected unexported and untagged fields to be skipped: %+v", cfg)
+	}
+}
+
+func TestBindStruct_ValidationErrors(t *testing.T) {
+	var cfg bindConfig
+	err := envvalidator.BindStruct(context.Background(), map[string]string{"PORT": "abc"}, &cfg)
+	verrs, ok := err.(envvalidator.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors, got %T: %v", err, err)
+	}
+	if len(verrs) != 2 {
+		t.Errorf("expected 2 errors (PORT, DATABASE_URL), got %d: %v", len(verrs), verrs)
+	}
+}
+
+func TestBindStruct_Overflow(t *testing.T) {
+	var cfg struct {
+		Workers uint8 `env:"WORKERS"`
+	}
+	err := envvalidator.BindStruct(context.Background(), map[string]string{"WORKERS": "300"}, &cfg)
+	if err == nil || !strings.Contains(err.Error(), "overflows uint8") {
+		t.Errorf("expected overflow error, got %v", err)
+	}
+}
+
+func TestBindStruct_InvalidTarget(t *testing.T) {
+	var cfg bindConfig
+	if err := envvalidator.BindStruct(context.Background(), nil, cfg); err == nil {
+		t.Error("expected error for non-pointer target, got nil")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type config struct {
+		Port    int
+		Timeout time.Duration
+	}
+	fields := []envvalidator.Field{
+		{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+		{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Required: true},
+	}
+	build := func(r *envvalidator.Result) config {
+		return config{Port: r.Port("PORT"), Timeout: r.Duration("TIMEOUT")}
+	}
+
+	cfg, err := envvalidator.Decode(context.Background(), map[string]string{"TIMEOUT": "2s"}, fields, build)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != (config{Port: 8080, Timeout: 2 * time.Second}) {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	called := false
+	cfg, err = envvalidator.Decode(context.Background(), map[string]string{"PORT": "abc"}, fields, func(r *envvalidator.Result) config {
+		called = true
+		return build(r)
+	})
+	if verrs, ok := err.(envvalidator.ValidationErrors); !ok || len(verrs) != 2 {
+		t.Errorf("expected 2 validation errors, got %v", err)
+	}
+	if called || cfg != (config{}) {
+		t.Errorf("expected zero config without calling assign, got %+v (called %v)", cfg, called)
+	}
+}
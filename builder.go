@@ -0,0 +1,243 @@
+package envvalidator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// FieldBuilder constructs a Field fluently. It is created by NewField, and
+// every setter returns the same builder so calls can be chained. The zero
+// value is not useful; always start from NewField.
+//
+// Example:
+//
+//	port := envvalidator.NewField("PORT").
+//	    Integer().
+//	    Default("8080").
+//	    Min(1).
+//	    Max(65535).
+//	    Describe("HTTP port").
+//	    MustBuild()
+type FieldBuilder struct {
+	f Field
+}
+
+// NewField starts building a Field with the given key.
+//
+// Example:
+//
+//	f, err := envvalidator.NewField("DATABASE_URL").URL().Required().Secret().Build()
+func NewField(key string) *FieldBuilder {
+	return &FieldBuilder{f: Field{Key: key}}
+}
+
+// Kind sets the field Kind.
+func (b *FieldBuilder) Kind(k Kind) *FieldBuilder {
+	b.f.Kind = k
+	return b
+}
+
+// String sets the field Kind to KindString.
+func (b *FieldBuilder) String() *FieldBuilder { return b.Kind(KindString) }
+
+// Integer sets the field Kind to KindInteger.
+func (b *FieldBuilder) Integer() *FieldBuilder { return b.Kind(KindInteger) }
+
+// Float sets the field Kind to KindFloat.
+func (b *FieldBuilder) Float() *FieldBuilder { return b.Kind(KindFloat) }
+
+// Boolean sets the field Kind to KindBoolean.
+func (b *FieldBuilder) Boolean() *FieldBuilder { return b.Kind(KindBoolean) }
+
+// URL sets the field Kind to KindURL.
+func (b *FieldBuilder) URL() *FieldBuilder { return b.Kind(KindURL) }
+
+// Duration sets the field Kind to KindDuration.
+func (b *FieldBuilder) Duration() *FieldBuilder { return b.Kind(KindDuration) }
+
+// Port sets the field Kind to KindPort.
+func (b *FieldBuilder) Port() *FieldBuilder { return b.Kind(KindPort) }
+
+// Alias sets Field.Alias.
+func (b *FieldBuilder) Alias(name string) *FieldBuilder {
+	b.f.Alias = name
+	return b
+}
+
+// Required marks the field as required.
+func (b *FieldBuilder) Required() *FieldBuilder {
+	b.f.Required = true
+	return b
+}
+
+// Default sets Field.Default.
+func (b *FieldBuilder) Default(value string) *FieldBuilder {
+	b.f.Default = value
+	return b
+}
+
+// Describe sets Field.Description.
+func (b *FieldBuilder) Describe(description string) *FieldBuilder {
+	b.f.Description = description
+	return b
+}
+
+// Allowed sets Field.AllowedValues.
+func (b *FieldBuilder) Allowed(values ...string) *FieldBuilder {
+	b.f.AllowedValues = values
+	return b
+}
+
+// Secret marks the field as Secret.
+func (b *FieldBuilder) Secret() *FieldBuilder {
+	b.f.Secret = true
+	return b
+}
+
+// Pattern sets Field.Pattern.
+func (b *FieldBuilder) Pattern(pattern string) *FieldBuilder {
+	b.f.Pattern = pattern
+	return b
+}
+
+// Min sets the inclusive lower bound for a KindInteger or KindBytes field.
+func (b *FieldBuilder) Min(n int64) *FieldBuilder {
+	b.f.Min = &n
+	return b
+}
+
+// Max sets the inclusive upper bound for a KindInteger or KindBytes field.
+func (b *FieldBuilder) Max(n int64) *FieldBuilder {
+	b.f.Max = &n
+	return b
+}
+
+// MinFloat sets the inclusive lower bound for a KindFloat field.
+func (b *FieldBuilder) MinFloat(n float64) *FieldBuilder {
+	b.f.MinFloat = &n
+	return b
+}
+
+// MaxFloat sets the inclusive upper bound for a KindFloat field.
+func (b *FieldBuilder) MaxFloat(n float64) *FieldBuilder {
+	b.f.MaxFloat = &n
+	return b
+}
+
+// MinDuration sets the inclusive lower bound for a KindDuration field.
+func (b *FieldBuilder) MinDuration(d time.Duration) *FieldBuilder {
+	b.f.MinDuration = &d
+	return b
+}
+
+// MaxDuration sets the inclusive upper bound for a KindDuration field.
+func (b *FieldBuilder) MaxDuration(d time.Duration) *FieldBuilder {
+	b.f.MaxDuration = &d
+	return b
+}
+
+// MinLen sets the minimum length of a KindString field.
+func (b *FieldBuilder) MinLen(n int) *FieldBuilder {
+	b.f.MinLen = n
+	return b
+}
+
+// MaxLen sets the maximum length of a KindString field.
+func (b *FieldBuilder) MaxLen(n int) *FieldBuilder {
+	b.f.MaxLen = n
+	return b
+}
+
+// Validate sets Field.Validate.
+func (b *FieldBuilder) Validate(fn func(raw string) error) *FieldBuilder {
+	b.f.Validate = fn
+	return b
+}
+
+// ValidateCtx sets Field.ValidateCtx.
+func (b *FieldBuilder) ValidateCtx(fn func(ctx context.Context, raw string) error) *FieldBuilder {
+	b.f.ValidateCtx = fn
+	return b
+}
+
+// Build returns the constructed Field, or an error describing every
+// contradictory combination of settings: Required together with a Default,
+// integer or float bounds on a field of another Kind, a lower bound above
+// the upper bound, and a Pattern that is not a valid regular expression.
+//
+// Example:
+//
+//	f, err := envvalidator.NewField("WORKERS").Integer().Min(1).Build()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (b *FieldBuilder) Build() (Field, error) {
+	f := b.f
+	kind := f.Kind
+	if kind == "" {
+		kind = KindString
+	}
+	if kind == KindList && f.ElementKind != "" {
+		kind = f.ElementKind
+	}
+
+	var problems []string
+	if f.Required && f.Default != "" {
+		problems = append(problems, "Required and Default are mutually exclusive")
+	}
+	if (f.Min != nil || f.Max != nil) && kind != KindInteger && kind != KindBytes {
+		problems = append(problems, fmt.Sprintf("Min and Max apply to %s and %s fields, not %s", KindInteger, KindBytes, kind))
+	}
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		problems = append(problems, fmt.Sprintf("Min %d is greater than Max %d", *f.Min, *f.Max))
+	}
+	if (f.MinFloat != nil || f.MaxFloat != nil) && kind != KindFloat {
+		problems = append(problems, fmt.Sprintf("MinFloat and MaxFloat apply to %s fields, not %s", KindFloat, kind))
+	}
+	if f.MinFloat != nil && f.MaxFloat != nil && *f.MinFloat > *f.MaxFloat {
+		problems = append(problems, fmt.Sprintf("MinFloat %g is greater than MaxFloat %g", *f.MinFloat, *f.MaxFloat))
+	}
+	if (f.MinDuration != nil || f.MaxDuration != nil) && kind != KindDuration {
+		problems = append(problems, fmt.Sprintf("MinDuration and MaxDuration apply to %s fields, not %s", KindDuration, kind))
+	}
+	if f.MinDuration != nil && f.MaxDuration != nil && *f.MinDuration > *f.MaxDuration {
+		problems = append(problems, fmt.Sprintf("MinDuration %s is greater than MaxDuration %s", *f.MinDuration, *f.MaxDuration))
+	}
+	if (f.MinLen > 0 || f.MaxLen > 0) && kind != KindString {
+		problems = append(problems, fmt.Sprintf("MinLen and MaxLen apply to %s fields, not %s", KindString, kind))
+	}
+	if f.MinLen > 0 && f.MaxLen > 0 && f.MinLen > f.MaxLen {
+		problems = append(problems, fmt.Sprintf("MinLen %d is greater than MaxLen %d", f.MinLen, f.MaxLen))
+	}
+	if f.Pattern != "" {
+		if _, err := regexp.Compile(f.Pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("pattern %q is not a valid regular expression", f.Pattern))
+		}
+	}
+
+	if len(problems) > 0 {
+		errs := make([]error, len(problems))
+		for i, p := range problems {
+			errs[i] = fmt.Errorf("env-validator: field %q: %s", f.Key, p)
+		}
+		return Field{}, errors.Join(errs...)
+	}
+	return f, nil
+}
+
+// MustBuild is like Build but panics if the settings are contradictory. It
+// is intended for package-level field declarations.
+//
+// Example:
+//
+//	var logLevel = envvalidator.NewField("LOG_LEVEL").Allowed("debug", "info").Default("info").MustBuild()
+func (b *FieldBuilder) MustBuild() Field {
+	f, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,56 @@
+package envvalidator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func TestFieldBuilder(t *testing.T) {
+	port := envvalidator.NewField("PORT").Integer().Default("8080").Min(1).Max(65535).Describe("HTTP port").MustBuild()
+	if port.Key != "PORT" || port.Kind != envvalidator.KindInteger || port.Default != "8080" || port.Description != "HTTP port" {
+		t.Fatalf("unexpected field: %+v", port)
+	}
+	if *port.Min != 1 || *port.Max != 65535 {
+		t.Errorf("unexpected bounds: %d..%d", *port.Min, *port.Max)
+	}
+
+	result, err := envvalidator.New(port).ValidateMap(context.Background(), map[string]string{"PORT": "9090"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Integer("PORT") != 9090 {
+		t.Errorf("expected 9090, got %d", result.Integer("PORT"))
+	}
+}
+
+func TestFieldBuilder_Contradictions(t *testing.T) {
+	cases := []struct {
+		name    string
+		builder *envvalidator.FieldBuilder
+		wantErr string
+	}{
+		{"required with default", envvalidator.NewField("PORT").Integer().Required().Default("8080"), "Required and Default are mutually exclusive"},
+		{"min above max", envvalidator.NewField("WORKERS").Integer().Min(10).Max(1), "Min 10 is greater than Max 1"},
+		{"integer bounds on string", envvalidator.NewField("NAME").Min(1), "Min and Max apply to integer and bytes fields, not string"},
+		{"float bounds on integer", envvalidator.NewField("RATE").Integer().MaxFloat(1), "MinFloat and MaxFloat apply to float fields, not integer"},
+		{"duration bounds on integer", envvalidator.NewField("RATE").Integer().MinDuration(time.Second), "MinDuration and MaxDuration apply to duration fields, not integer"},
+		{"bad pattern", envvalidator.NewField("NAME").Pattern("("), `pattern "(" is not a valid regular expression`},
+	}
+	for _, tc := range cases {
+		_, err := tc.builder.Build()
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustBuild to panic")
+		}
+	}()
+	envvalidator.NewField("PORT").Required().Default("8080").MustBuild()
+}
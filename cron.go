@@ -0,0 +1,144 @@
+package envvalidator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CronSchedule is the parsed form of a KindCron value. Each field lists the
+// values it matches in ascending order. Seconds is nil for a standard
+// five-field expression.
+type CronSchedule struct {
+	Seconds     []int
+	Minutes     []int
+	Hours       []int
+	DaysOfMonth []int
+	Months      []int
+	// DaysOfWeek uses 0 for Sunday; 7 is accepted as an alias for 0.
+	DaysOfWeek []int
+
+	expr string
+}
+
+// String returns the expression the schedule was parsed from, with runs of
+// whitespace collapsed to single spaces.
+func (cs CronSchedule) String() string {
+	return cs.expr
+}
+
+// cronField describes one position of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+// parseCron parses s as a five-field cron expression, or, when
+// allowSeconds is true, optionally a six-field one starting with seconds.
+// The returned problem names the offending field position without echoing
+// the input.
+func parseCron(s string, allowSeconds bool) (CronSchedule, string) {
+	var cs CronSchedule
+	parts := strings.Fields(s)
+	fields := []cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+		{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+	}
+	targets := []*[]int{&cs.Minutes, &cs.Hours, &cs.DaysOfMonth, &cs.Months, &cs.DaysOfWeek}
+	switch {
+	case len(parts) == 6 && allowSeconds:
+		fields = append([]cronField{{name: "second", min: 0, max: 59}}, fields...)
+		targets = append([]*[]int{&cs.Seconds}, targets...)
+	case len(parts) == 6:
+		return cs, "expected 5 fields; set CronSeconds to allow a seconds field"
+	case len(parts) != 5 && allowSeconds:
+		return cs, fmt.Sprintf("expected 5 or 6 fields, got %d", len(parts))
+	case len(parts) != 5:
+		return cs, fmt.Sprintf("expected 5 fields, got %d", len(parts))
+	}
+	for i, part := range parts {
+		values, problem := parseCronField(part, fields[i])
+		if problem != "" {
+			return cs, fmt.Sprintf("field %d (%s): %s", i+1, fields[i].name, problem)
+		}
+		*targets[i] = values
+	}
+	cs.expr = strings.Join(parts, " ")
+	return cs, ""
+}
+
+// parseCronField parses one comma-separated cron field and returns the
+// matched values in ascending order.
+func parseCronField(s string, f cronField) ([]int, string) {
+	seen := make([]bool, f.max+1)
+	for _, item := range strings.Split(s, ",") {
+		rng, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return nil, "step must be a positive integer"
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			loText, hiText, _ := strings.Cut(rng, "-")
+			var problem string
+			if lo, problem = cronValue(loText, f); problem != "" {
+				return nil, problem
+			}
+			if hi, problem = cronValue(hiText, f); problem != "" {
+				return nil, problem
+			}
+			if lo > hi {
+				return nil, fmt.Sprintf("range %d-%d is reversed", lo, hi)
+			}
+		default:
+			var problem string
+			if lo, problem = cronValue(rng, f); problem != "" {
+				return nil, problem
+			}
+			if !hasStep {
+				hi = lo
+			}
+		}
+		for n := lo; n <= hi; n += step {
+			seen[n] = true
+		}
+	}
+	if f.name == "day of week" && seen[7] {
+		seen[0], seen[7] = true, false
+	}
+	var values []int
+	for n, ok := range seen {
+		if ok {
+			values = append(values, n)
+		}
+	}
+	return values, ""
+}
+
+// cronValue parses a single number or, for months and days of the week, a
+// three-letter name, and checks it against the field's range.
+func cronValue(s string, f cronField) (int, string) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, ""
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || s == "" || s[0] == '+' {
+		return 0, "expected a number, a range, or *"
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Sprintf("value %d is out of range %d-%d", n, f.min, f.max)
+	}
+	return n, ""
+}
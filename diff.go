@@ -0,0 +1,68 @@
+package envvalidator
+
+import "context"
+
+// FieldDiff describes how one field's validated value differs between two
+// environments. Values are in canonical text form, so "120s" and "2m" compare
+// equal for a KindDuration field.
+type FieldDiff struct {
+	// Key is the field key as declared.
+	Key string `json:"key"`
+
+	// OldValue is the value parsed from the "before" environment. It is
+	// "<redacted>" for Secret fields.
+	OldValue string `json:"old_value"`
+
+	// NewValue is the value parsed from the "after" environment. It is
+	// "<redacted>" for Secret fields.
+	NewValue string `json:"new_value"`
+
+	// Changed reports whether the parsed values differ. It is computed from
+	// the real values, so a changed secret is still reported.
+	Changed bool `json:"changed"`
+}
+
+// Diff validates both environments and compares the parsed values of every
+// declared field, returning one FieldDiff per field in declaration order. If
+// either environment fails validation, that error is returned and no diff is
+// produced.
+//
+// Example:
+//
+//	diffs, err := v.Diff(ctx, oldEnv, newEnv)
+//	for _, d := range diffs {
+//	    if d.Changed {
+//	        fmt.Printf("%s: %s -> %s\n", d.Key, d.OldValue, d.NewValue)
+//	    }
+//	}
+func (v *Validator) Diff(ctx context.Context, before, after map[string]string) ([]FieldDiff, error) {
+	oldResult, err := v.ValidateMap(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	newResult, err := v.ValidateMap(ctx, after)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]FieldDiff, 0, len(v.fields))
+	seen := make(map[string]bool, len(v.fields))
+	for _, f := range v.fields {
+		key := resultKey(f)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		d := FieldDiff{
+			Key:      key,
+			OldValue: oldResult.text(key),
+			NewValue: newResult.text(key),
+		}
+		d.Changed = d.OldValue != d.NewValue
+		if f.Secret {
+			d.OldValue, d.NewValue = redactedValue, redactedValue
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs, nil
+}
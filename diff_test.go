@@ -0,0 +1,46 @@
+package envvalidator_test
+
+import (
+	"context"
+	"testing"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func TestDiff(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Default: "8080"},
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "30s"},
+		envvalidator.Field{Key: "API_KEY", Required: true, Secret: true},
+	)
+	diffs, err := v.Diff(context.Background(),
+		map[string]string{"PORT": "8080", "TIMEOUT": "120s", "API_KEY": "old"},
+		map[string]string{"PORT": "9090", "TIMEOUT": "2m", "API_KEY": "new"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []envvalidator.FieldDiff{
+		{Key: "PORT", OldValue: "8080", NewValue: "9090", Changed: true},
+		{Key: "TIMEOUT", OldValue: "2m0s", NewValue: "2m0s", Changed: false},
+		{Key: "API_KEY", OldValue: "<redacted>", NewValue: "<redacted>", Changed: true},
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("expected %d diffs, got %d: %+v", len(expected), len(diffs), diffs)
+	}
+	for i := range expected {
+		if diffs[i] != expected[i] {
+			t.Errorf("diff %d: expected %+v, got %+v", i, expected[i], diffs[i])
+		}
+	}
+}
+
+func TestDiff_InvalidEnvironment(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Required: true},
+	)
+	_, err := v.Diff(context.Background(), map[string]string{"PORT": "1"}, map[string]string{"PORT": "x"})
+	if _, ok := err.(envvalidator.ValidationErrors); !ok {
+		t.Errorf("expected ValidationErrors, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,155 @@
+package envvalidator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// DotEnvError describes a malformed line encountered while parsing a dotenv
+// file.
+type DotEnvError struct {
+	// Line is the 1-based line number of the malformed line.
+	Line int
+
+	// Reason is a human-readable description of what is wrong with the line.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *DotEnvError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// LoadDotEnv reads a dotenv file and returns its KEY=VALUE pairs as a map
+// suitable for ValidateMap. It does not modify the process environment.
+//
+// Blank lines and lines starting with # are ignored, an optional leading
+// "export " is accepted, and a value wrapped in matching single or double
+// quotes has the quotes stripped. A malformed line produces an error that
+// wraps a *DotEnvError carrying the line number.
+//
+// Example:
+//
+//	env, err := envvalidator.LoadDotEnv(".env")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	result, err := v.ValidateMap(context.Background(), env)
+func LoadDotEnv(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("env-validator: %w", err)
+	}
+	defer f.Close()
+
+	env, err := parseDotEnv(f)
+	if err != nil {
+		return nil, fmt.Errorf("env-validator: %s: %w", path, err)
+	}
+	return env, nil
+}
+
+// LoadDotEnvFiles reads each dotenv file in order with LoadDotEnv and
+// returns the merged pairs, with later files overriding earlier ones, for
+// layouts such as .env, .env.local, and .env.<profile>. When skipMissing is
+// true, files that do not exist are ignored; any other error, including a
+// malformed line, still fails. With no paths it returns an empty map.
+//
+// Example:
+//
+//	env, err := envvalidator.LoadDotEnvFiles(true, ".env", ".env.local", ".env."+profile)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	result, err := v.ValidateMap(context.Background(), env)
+func LoadDotEnvFiles(skipMissing bool, paths ...string) (map[string]string, error) {
+	merged := make(map[string]string)
+	for _, path := range paths {
+		env, err := LoadDotEnv(path)
+		if skipMissing && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for k, val := range env {
+			merged[k] = val
+		}
+	}
+	return merged, nil
+}
+
+// ValidateReader reads KEY=VALUE lines from r using the same rules as
+// LoadDotEnv and validates them like ValidateMap. It suits piped input and
+// config blobs embedded with //go:embed.
+//
+// A malformed line is reported as an error wrapping a *DotEnvError with its
+// line number, before any validation happens, so it can be told apart from
+// the ValidationErrors returned for invalid values.
+//
+// Example:
+//
+//	//go:embed defaults.env
+//	var defaults string
+//
+//	result, err := v.ValidateReader(context.Background(), strings.NewReader(defaults))
+func (v *Validator) ValidateReader(ctx context.Context, r io.Reader) (*Result, error) {
+	env, err := parseDotEnv(r)
+	if err != nil {
+		return nil, fmt.Errorf("env-validator: %w", err)
+	}
+	return v.ValidateMap(ctx, env)
+}
+
+// parseDotEnv scans r line by line using the dotenv rules documented on
+// LoadDotEnv. Later occurrences of a key override earlier ones.
+func parseDotEnv(r io.Reader) (map[string]string, error) {
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, &DotEnvError{Line: line, Reason: fmt.Sprintf("expected KEY=VALUE, got a line starting with %q", firstWord(text))}
+		}
+		key = strings.TrimSpace(key)
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, &DotEnvError{Line: line, Reason: fmt.Sprintf("invalid key starting with %q", firstWord(key))}
+		}
+		value = strings.TrimSpace(value)
+		if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') {
+			if value[n-1] != value[0] {
+				return nil, &DotEnvError{Line: line, Reason: fmt.Sprintf("unterminated quoted value for key %q", key)}
+			}
+			value = value[1 : n-1]
+		}
+		env[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// firstWord returns s up to its first space or tab. Malformed-line errors
+// quote only this much so that a value on the line, which may be a secret,
+// is never echoed.
+func firstWord(s string) string {
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,170 @@
+package envvalidator_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
+
+func TestLoadDotEnv_Parses(t *testing.T) {
+	path := writeFile(t, ".env", `# local development settings
+
+PORT=9090
+DATABASE_URL="postgres://localhost/dev"
+GREETING='hello world'
+export LOG_LEVEL = debug
+EMPTY=
+`)
+	env, err := envvalidator.LoadDotEnv(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"PORT":         "9090",
+		"DATABASE_URL": "postgres://localhost/dev",
+		"GREETING":     "hello world",
+		"LOG_LEVEL":    "debug",
+		"EMPTY":        "",
+	}
+	if len(env) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %v", len(expected), len(env), env)
+	}
+	for k, want := range expected {
+		if env[k] != want {
+			t.Errorf("key %s: expected %q, got %q", k, want, env[k])
+		}
+	}
+
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Required: true},
+	)
+	result, err := v.ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if result.Integer("PORT") != 9090 {
+		t.Errorf("expected 9090, got %d", result.Integer("PORT"))
+	}
+}
+
+func TestLoadDotEnv_MalformedLine(t *testing.T) {
+	path := writeFile(t, ".env", "PORT=9090\n\nthis is not valid\n")
+	_, err := envvalidator.LoadDotEnv(path)
+	if err == nil {
+		t.Fatal("expected error for malformed line, got nil")
+	}
+	var de *envvalidator.DotEnvError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected *DotEnvError in chain, got %T: %v", err, err)
+	}
+	if de.Line != 3 {
+		t.Errorf("expected line 3, got %d", de.Line)
+	}
+}
+
+func TestLoadDotEnv_MalformedLineDoesNotEchoValue(t *testing.T) {
+	cases := []struct {
+		content, want string
+	}{
+		{"API_KEY sk-live-abc123\n", `line 1: expected KEY=VALUE, got a line starting with "API_KEY"`},
+		{"API_KEY sk-live-abc123=x\n", `line 1: invalid key starting with "API_KEY"`},
+	}
+	for _, tc := range cases {
+		path := writeFile(t, ".env", tc.content)
+		_, err := envvalidator.LoadDotEnv(path)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q: expected error containing %q, got %v", tc.content, tc.want, err)
+		}
+		if err != nil && strings.Contains(err.Error(), "sk-live") {
+			t.Errorf("%q: error echoes the value: %v", tc.content, err)
+		}
+	}
+}
+
+func TestLoadDotEnv_MissingFile(t *testing.T) {
+	_, err := envvalidator.LoadDotEnv(filepath.Join(t.TempDir(), "absent.env"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestLoadDotEnvFiles_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".env")
+	local := filepath.Join(dir, ".env.local")
+	prod := filepath.Join(dir, ".env.prod")
+	for path, content := range map[string]string{
+		base: "PORT=8080\nLOG_LEVEL=info\nREGION=eu\n",
+		prod: "LOG_LEVEL=warn\nREGION=us\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env, err := envvalidator.LoadDotEnvFiles(true, base, local, prod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"PORT": "8080", "LOG_LEVEL": "warn", "REGION": "us"}
+	if len(env) != len(want) {
+		t.Fatalf("expected %v, got %v", want, env)
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, env[k])
+		}
+	}
+
+	if _, err := envvalidator.LoadDotEnvFiles(false, base, local); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist without skipMissing, got %v", err)
+	}
+	bad := writeFile(t, ".env.bad", "NOT A LINE\n")
+	var dotErr *envvalidator.DotEnvError
+	if _, err := envvalidator.LoadDotEnvFiles(true, base, bad); !errors.As(err, &dotErr) {
+		t.Errorf("expected a *DotEnvError even with skipMissing, got %v", err)
+	}
+}
+
+func TestValidateReader(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Required: true},
+		envvalidator.Field{Key: "GREETING", Default: "hi"},
+	)
+	input := "# piped config\nexport PORT=9090\n\nGREETING='hello world'\n"
+	result, err := v.ValidateReader(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("PORT") != 9090 || result.String("GREETING") != "hello world" {
+		t.Errorf("unexpected values: %v", result.Redacted())
+	}
+
+	_, err = v.ValidateReader(context.Background(), strings.NewReader("PORT=9090\nnot valid\n"))
+	var de *envvalidator.DotEnvError
+	if !errors.As(err, &de) || de.Line != 2 {
+		t.Errorf("expected DotEnvError on line 2, got %v", err)
+	}
+	if _, ok := err.(envvalidator.ValidationErrors); ok {
+		t.Error("expected a parse error, not ValidationErrors")
+	}
+
+	_, err = v.ValidateReader(context.Background(), strings.NewReader("PORT=http\n"))
+	if !errors.Is(err, envvalidator.ErrInvalidValue) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
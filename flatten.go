@@ -0,0 +1,144 @@
+package envvalidator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FlattenJSON flattens a JSON object into UPPER_SNAKE keys suitable for
+// ValidateMap, so a mounted JSON config file can be validated with the same
+// fields as the environment.
+//
+// Nested object keys are joined with "_", so {"db":{"url":"..."}} becomes
+// DB_URL. camelCase names are split at case changes and other punctuation is
+// replaced with "_", so "maxConns" and "max-conns" both become MAX_CONNS.
+// Strings are used as-is, numbers keep their original text, booleans become
+// "true" or "false", and null leaves are omitted. Arrays of scalars are
+// joined with "," to match the default KindList delimiter; arrays containing
+// objects or arrays are rejected. It is an error for two paths to flatten to
+// the same key.
+//
+// Example:
+//
+//	data, err := os.ReadFile("config.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	env, err := envvalidator.FlattenJSON(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	result, err := v.ValidateMap(context.Background(), env)
+func FlattenJSON(data []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root any
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("env-validator: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("env-validator: unexpected data after top-level JSON value")
+	}
+	obj, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("env-validator: top-level JSON value must be an object")
+	}
+
+	env := make(map[string]string)
+	paths := make(map[string]string)
+	if err := flattenObject(obj, "", "", env, paths); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// flattenObject adds the leaves of obj to env. prefix is the flattened key of
+// obj itself and path its dotted JSON path, used in error messages; paths
+// records which JSON path produced each key so collisions can be reported.
+func flattenObject(obj map[string]any, prefix, path string, env, paths map[string]string) error {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := snakeKey(name)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		childPath := name
+		if path != "" {
+			childPath = path + "." + name
+		}
+
+		var value string
+		switch val := obj[name].(type) {
+		case nil:
+			continue
+		case map[string]any:
+			if err := flattenObject(val, key, childPath, env, paths); err != nil {
+				return err
+			}
+			continue
+		case []any:
+			elems := make([]string, len(val))
+			for i, elem := range val {
+				s, ok := jsonScalar(elem)
+				if !ok {
+					return fmt.Errorf("env-validator: %s: arrays may only contain strings, numbers, and booleans", childPath)
+				}
+				elems[i] = s
+			}
+			value = strings.Join(elems, ",")
+		default:
+			value, _ = jsonScalar(val)
+		}
+
+		if other, ok := paths[key]; ok {
+			return fmt.Errorf("env-validator: %s and %s both flatten to %s", other, childPath, key)
+		}
+		paths[key] = childPath
+		env[key] = value
+	}
+	return nil
+}
+
+// jsonScalar renders a decoded JSON string, number, or boolean as text.
+func jsonScalar(v any) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case json.Number:
+		return val.String(), true
+	case bool:
+		if val {
+			return "true", true
+		}
+		return "false", true
+	}
+	return "", false
+}
+
+// snakeKey converts a JSON object key to UPPER_SNAKE form.
+func snakeKey(name string) string {
+	var b strings.Builder
+	var prev rune
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteByte('_')
+		}
+		prev = r
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package envvalidator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	data := []byte(`{
+		"db": {"url": "postgres://localhost/app", "maxConns": 20, "pool": {"idle-timeout": "30s"}},
+		"debug": true,
+		"ratio": 1.50,
+		"hosts": ["a.example.com", "b.example.com"],
+		"unset": null
+	}`)
+	env, err := envvalidator.FlattenJSON(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"DB_URL":               "postgres://localhost/app",
+		"DB_MAX_CONNS":         "20",
+		"DB_POOL_IDLE_TIMEOUT": "30s",
+		"DEBUG":                "true",
+		"RATIO":                "1.50",
+		"HOSTS":                "a.example.com,b.example.com",
+	}
+	if len(env) != len(want) {
+		t.Errorf("expected %d keys, got %v", len(want), env)
+	}
+	for k, w := range want {
+		if env[k] != w {
+			t.Errorf("%s: expected %q, got %q", k, w, env[k])
+		}
+	}
+
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DB_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "HOSTS", Kind: envvalidator.KindList, Required: true},
+	)
+	result, err := v.ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if got := result.Strings("HOSTS"); len(got) != 2 {
+		t.Errorf("expected two hosts, got %v", got)
+	}
+}
+
+func TestFlattenJSON_Errors(t *testing.T) {
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{`[1, 2]`, "top-level JSON value must be an object"},
+		{`{"a": 1} {"b": 2}`, "unexpected data after top-level JSON value"},
+		{`{"a": `, "unexpected EOF"},
+		{`{"servers": [{"host": "a"}]}`, "servers: arrays may only contain strings, numbers, and booleans"},
+		{`{"db": {"url": "x"}, "db_url": "y"}`, "db.url and db_url both flatten to DB_URL"},
+	}
+	for _, tc := range cases {
+		_, err := envvalidator.FlattenJSON([]byte(tc.input))
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package envvalidator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// groupRule is the number of members a fieldGroup allows to be set.
+type groupRule int
+
+const (
+	atMostOne groupRule = iota
+	exactlyOne
+	atLeastOne
+)
+
+// fieldGroup is a named constraint on how many of its member fields may be
+// set at once.
+type fieldGroup struct {
+	name string
+	keys []string
+	rule groupRule
+}
+
+// ExclusiveGroup declares that at most one of the fields identified by keys
+// may be set. If more than one is present (non-empty) in the input, the
+// validation reports an error wrapping ErrConflict that names the group and
+// the conflicting variables. Keys are Field keys, as used with Result
+// accessors. It returns v so declarations can be chained.
+//
+// Example:
+//
+//	v := envvalidator.New(
+//	    envvalidator.Field{Key: "STATIC_TOKEN", Secret: true},
+//	    envvalidator.Field{Key: "OAUTH_CLIENT_ID"},
+//	).ExclusiveGroup("auth", "STATIC_TOKEN", "OAUTH_CLIENT_ID")
+func (v *Validator) ExclusiveGroup(name string, keys ...string) *Validator {
+	v.groups = append(v.groups, fieldGroup{name: name, keys: keys, rule: atMostOne})
+	return v
+}
+
+// ExactlyOneOf is like ExclusiveGroup but also requires one member to be set.
+// When none is present the error wraps ErrRequiredMissing. Defaults do not
+// count as being set.
+//
+// Example:
+//
+//	v.ExactlyOneOf("auth", "STATIC_TOKEN", "OAUTH_CLIENT_ID")
+func (v *Validator) ExactlyOneOf(name string, keys ...string) *Validator {
+	v.groups = append(v.groups, fieldGroup{name: name, keys: keys, rule: exactlyOne})
+	return v
+}
+
+// RequireAnyOf declares that at least one of the fields identified by keys
+// must be set, without forcing all of them as Required would. When none is
+// present (non-empty) the validation reports a single error wrapping
+// ErrRequiredMissing that names the group and lists the options. Defaults do
+// not count as being set.
+//
+// Example:
+//
+//	v.RequireAnyOf("notifier", "SLACK_WEBHOOK", "EMAIL_TO", "PAGERDUTY_KEY")
+func (v *Validator) RequireAnyOf(name string, keys ...string) *Validator {
+	v.groups = append(v.groups, fieldGroup{name: name, keys: keys, rule: atLeastOne})
+	return v
+}
+
+// checkGroups evaluates every declared group against the raw input and
+// returns one error per violated group, in declaration order.
+func (v *Validator) checkGroups(env map[string]string) ValidationErrors {
+	var errs ValidationErrors
+	for _, g := range v.groups {
+		names := make([]string, len(g.keys))
+		var present []string
+		for i, key := range g.keys {
+			f := v.groupField(key)
+			names[i] = v.envKey(f)
+			if _, source := v.lookup(f, env); source != "" {
+				present = append(present, names[i])
+			}
+		}
+		switch {
+		case len(present) > 1 && g.rule != atLeastOne:
+			errs = append(errs, &ValidationError{
+				Key:    present[0],
+				Reason: fmt.Sprintf("group %q: %s are mutually exclusive; set only one", g.name, strings.Join(present, ", ")),
+				Err:    ErrConflict,
+			})
+		case len(present) == 0 && g.rule != atMostOne && len(names) > 0:
+			quantity := "exactly one"
+			if g.rule == atLeastOne {
+				quantity = "at least one"
+			}
+			errs = append(errs, &ValidationError{
+				Key:    names[0],
+				Reason: fmt.Sprintf("group %q: %s of %s must be set", g.name, quantity, strings.Join(names, ", ")),
+				Err:    ErrRequiredMissing,
+			})
+		}
+	}
+	return errs
+}
+
+// groupField returns the declaration for key, or a plain Field with that key
+// if it was not declared.
+func (v *Validator) groupField(key string) Field {
+	for _, f := range v.fields {
+		if f.Key == key {
+			return f
+		}
+	}
+	return Field{Key: key}
+}
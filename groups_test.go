@@ -0,0 +1,101 @@
+package envvalidator_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func TestExclusiveGroup(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "STATIC_TOKEN", Secret: true},
+		envvalidator.Field{Key: "OAUTH_CLIENT_ID"},
+		envvalidator.Field{Key: "OAUTH_ISSUER", Default: "https://issuer.example.com"},
+	).ExclusiveGroup("auth", "STATIC_TOKEN", "OAUTH_CLIENT_ID")
+
+	for _, env := range []map[string]string{
+		{},
+		{"STATIC_TOKEN": "t0k3n"},
+		{"OAUTH_CLIENT_ID": "client"},
+	} {
+		if _, err := v.ValidateMap(context.Background(), env); err != nil {
+			t.Errorf("%v: unexpected error: %v", env, err)
+		}
+	}
+
+	_, err := v.ValidateMap(context.Background(), map[string]string{"STATIC_TOKEN": "t0k3n", "OAUTH_CLIENT_ID": "client"})
+	if !errors.Is(err, envvalidator.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	want := `group "auth": STATIC_TOKEN, OAUTH_CLIENT_ID are mutually exclusive; set only one`
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+	if strings.Contains(err.Error(), "t0k3n") {
+		t.Errorf("error echoes a secret value: %v", err)
+	}
+}
+
+func TestExactlyOneOf(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "STATIC_TOKEN"},
+		envvalidator.Field{Key: "OAUTH_CLIENT_ID", Default: "ignored"},
+	).ExactlyOneOf("auth", "STATIC_TOKEN", "OAUTH_CLIENT_ID")
+
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"APP_STATIC_TOKEN": "t"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{})
+	if !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Fatalf("expected ErrRequiredMissing, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `group "auth": exactly one of APP_STATIC_TOKEN, APP_OAUTH_CLIENT_ID must be set`) {
+		t.Errorf("unexpected message: %v", err)
+	}
+	_, err = v.ValidateMap(context.Background(), map[string]string{"APP_STATIC_TOKEN": "t", "APP_OAUTH_CLIENT_ID": "c"})
+	if !errors.Is(err, envvalidator.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+func TestLint_GroupUndeclaredKey(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "STATIC_TOKEN"}).ExclusiveGroup("auth", "STATIC_TOKEN", "OAUTH_CLIENTID")
+	got := v.Lint()
+	if len(got) != 1 || got[0].String() != `OAUTH_CLIENTID: group "auth" references an undeclared key` {
+		t.Errorf("unexpected issues: %v", got)
+	}
+}
+
+func TestRequireAnyOf(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SLACK_WEBHOOK", Fallbacks: []string{"SLACK_URL"}},
+		envvalidator.Field{Key: "EMAIL_TO", Kind: envvalidator.KindEmail, Default: "ops@example.com"},
+		envvalidator.Field{Key: "PAGERDUTY_KEY", Secret: true},
+	).RequireAnyOf("notifier", "SLACK_WEBHOOK", "EMAIL_TO", "PAGERDUTY_KEY")
+
+	for _, env := range []map[string]string{
+		{"PAGERDUTY_KEY": "k"},
+		{"SLACK_URL": "https://hooks.example.com/x"},
+		{"EMAIL_TO": "a@example.com", "PAGERDUTY_KEY": "k"},
+	} {
+		if _, err := v.ValidateMap(context.Background(), env); err != nil {
+			t.Errorf("%v: unexpected error: %v", env, err)
+		}
+	}
+
+	_, err := v.ValidateMap(context.Background(), map[string]string{})
+	errs, ok := err.(envvalidator.ValidationErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("expected a single error, got %v", err)
+	}
+	if !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Errorf("expected ErrRequiredMissing, got %v", err)
+	}
+	want := `group "notifier": at least one of SLACK_WEBHOOK, EMAIL_TO, PAGERDUTY_KEY must be set`
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,176 @@
+package envvalidator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// indexed reports whether f is an indexed field: one whose variable name,
+// Alias if set and Key otherwise, ends in "#", such as "ORIGIN_#". An
+// indexed field collects ORIGIN_0, ORIGIN_1, and so on into a list.
+func indexed(f Field) bool {
+	name := f.Key
+	if f.Alias != "" {
+		name = f.Alias
+	}
+	return strings.HasSuffix(name, "#")
+}
+
+// resultKey returns the key under which f's value is stored in a Result:
+// f.Key, or for an indexed field, f.Key without the trailing "#" and the
+// separator before it, so "ORIGIN_#" is stored as "ORIGIN".
+func resultKey(f Field) string {
+	if !indexed(f) {
+		return f.Key
+	}
+	return strings.TrimRight(strings.TrimSuffix(f.Key, "#"), "_")
+}
+
+// indexedList returns a copy of the indexed field f that reads a delimited
+// list of f's Kind, used for its Default and Fallbacks when no indexed
+// variable is set.
+func indexedList(f Field) Field {
+	f.ElementKind = f.Kind
+	if f.ElementKind == "" {
+		f.ElementKind = KindString
+	}
+	f.Kind, f.AnyOf = KindList, nil
+	return f
+}
+
+// indexNumber reports the index n in a variable name base+n, accepting only
+// canonical decimal numbers so ORIGIN_01 is not taken for ORIGIN_1.
+func indexNumber(name, base string) (int, bool) {
+	digits, ok := strings.CutPrefix(name, base)
+	if !ok || digits == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 || strconv.Itoa(n) != digits {
+		return 0, false
+	}
+	return n, true
+}
+
+// isIndexedVar reports whether name is one of the variables of an indexed
+// field, such as ORIGIN_2 for "ORIGIN_#".
+func (v *Validator) isIndexedVar(name string) bool {
+	for _, f := range v.fields {
+		if !indexed(f) {
+			continue
+		}
+		if _, ok := indexNumber(name, strings.TrimSuffix(v.envKey(f), "#")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// indexes returns, in ascending order, the indexes of the non-empty
+// variables base0, base1, ... found in env. With TreatBlankAsEmpty,
+// whitespace-only values count as empty.
+func (v *Validator) indexes(base string, env map[string]string) []int {
+	var out []int
+	for name, val := range env {
+		if v.TreatBlankAsEmpty && strings.TrimSpace(val) == "" {
+			val = ""
+		}
+		if n, ok := indexNumber(name, base); ok && val != "" {
+			out = append(out, n)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// validateIndexed validates an indexed field. Each variable base0, base1, ...
+// is validated like a variable of the field's own Kind, and the values are
+// stored as a list. The indexes must run from 0 without gaps. When none is
+// set, the field is validated as a delimited list so Required, Default, and
+// Fallbacks apply as usual.
+func (v *Validator) validateIndexed(ctx context.Context, f Field, env map[string]string, opts validateOptions) (fieldOutcome, *ValidationError) {
+	name := v.envKey(f)
+	base := strings.TrimSuffix(name, "#")
+	found := v.indexes(base, env)
+	if len(found) == 0 {
+		return v.validateField(ctx, indexedList(f), env, opts)
+	}
+
+	kind := f.Kind
+	if kind == "" {
+		kind = KindString
+	}
+	out := fieldOutcome{kind: KindList, source: name}
+	if f.Deprecated != "" {
+		out.warnings = append(out.warnings, warning{key: name, msg: fmt.Sprintf("%s is deprecated: %s", name, f.Deprecated)})
+	}
+	if f.AllowedValuesFunc != nil {
+		// Load the allowed values once for the field, not once per element.
+		out.ranCtxHook = true
+		allowed, err := f.AllowedValuesFunc(ctx)
+		if err != nil {
+			return out, &ValidationError{
+				Key:    name,
+				Reason: fmt.Sprintf("cannot load allowed values: %v", err),
+				Err:    fmt.Errorf("%w: %w", ErrInvalidValue, err),
+				Kind:   kind,
+			}
+		}
+		f.AllowedValues, f.AllowedValuesFunc = allowed, nil
+	}
+	items := make([]any, 0, len(found))
+	for i, n := range found {
+		if n != i {
+			missing := base + strconv.Itoa(i)
+			return out, &ValidationError{
+				Key:    missing,
+				Reason: fmt.Sprintf("%s is missing; indexed variables must be numbered from %s0 without gaps", missing, base),
+				Err:    ErrInvalidValue,
+				Kind:   kind,
+			}
+		}
+		elem := f
+		elem.Alias = strings.TrimPrefix(base+strconv.Itoa(i), v.prefix)
+		elem.Required, elem.RequiredIn, elem.RequiredIf = false, nil, nil
+		elem.Default, elem.DefaultFunc, elem.ExpandDefault = "", nil, false
+		elem.Fallbacks, elem.DeprecatedKeys, elem.Deprecated = nil, nil, ""
+		elemOut, err := v.validateField(ctx, elem, env, opts)
+		out.ranCtxHook = out.ranCtxHook || elemOut.ranCtxHook
+		if err != nil {
+			return out, err
+		}
+		items = append(items, elemOut.value)
+	}
+	if len(f.AnyOf) > 0 {
+		// Elements may have matched different kinds.
+		out.value = items
+	} else {
+		out.value = typedSlice(kind, items)
+	}
+	return out, nil
+}
+
+// variables returns the variable names and canonical text values that
+// reproduce the value stored under key: the field's own variable for an
+// ordinary field, or base0, base1, ... with one element each for an indexed
+// field.
+func (r *Result) variables(key string) (names, texts []string) {
+	name := r.names[key]
+	base, ok := strings.CutSuffix(name, "#")
+	if !ok {
+		return []string{name}, []string{r.text(key)}
+	}
+	rv := reflect.ValueOf(r.values[key])
+	if rv.Kind() != reflect.Slice {
+		return nil, nil
+	}
+	for i := 0; i < rv.Len(); i++ {
+		names = append(names, base+strconv.Itoa(i))
+		texts = append(texts, formatValue(rv.Index(i).Interface()))
+	}
+	return names, texts
+}
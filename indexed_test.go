@@ -0,0 +1,220 @@
+package envvalidator_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func TestIndexedField_CollectsValues(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "ORIGIN_#", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "WEIGHT_#", Kind: envvalidator.KindInteger, Default: "1,2"},
+	)
+	v.CaptureAll = true
+	result, err := v.ValidateMap(context.Background(), map[string]string{
+		"APP_ORIGIN_1": "https://b.example.com",
+		"APP_ORIGIN_0": "https://a.example.com",
+		"APP_OTHER":    "x",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"https://a.example.com", "https://b.example.com"}
+	if got := result.Strings("ORIGIN"); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, err := envvalidator.Get[[]int64](result, "WEIGHT"); err != nil || !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("expected default weights [1 2], got %v (%v)", got, err)
+	}
+	if got := result.Undeclared(); !reflect.DeepEqual(got, map[string]string{"APP_OTHER": "x"}) {
+		t.Errorf("expected indexed variables to count as declared, got %v", got)
+	}
+}
+
+func TestIndexedField_Errors(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "ORIGIN_#", Kind: envvalidator.KindURL, Required: true})
+	cases := []struct {
+		name    string
+		env     map[string]string
+		wantKey string
+		wantErr string
+	}{
+		{"missing", map[string]string{}, "ORIGIN_#", "required variable is missing"},
+		{"gap", map[string]string{"ORIGIN_0": "https://a.example.com", "ORIGIN_2": "https://c.example.com"}, "ORIGIN_1", "ORIGIN_1 is missing"},
+		{"not from zero", map[string]string{"ORIGIN_1": "https://b.example.com"}, "ORIGIN_0", "ORIGIN_0 is missing"},
+		{"invalid element", map[string]string{"ORIGIN_0": "https://a.example.com", "ORIGIN_1": "nope"}, "ORIGIN_1", "cannot parse"},
+	}
+	for _, tc := range cases {
+		_, err := v.ValidateMap(context.Background(), tc.env)
+		var errs envvalidator.ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Key != tc.wantKey || !strings.Contains(errs[0].Reason, tc.wantErr) {
+			t.Errorf("%s: expected %s error containing %q, got %v", tc.name, tc.wantKey, tc.wantErr, err)
+		}
+	}
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"ORIGIN_0": "https://a.example.com", "ORIGIN_01": "nope"}); err != nil {
+		t.Errorf("expected non-canonical indexes to be ignored, got %v", err)
+	}
+}
+
+func TestIndexedField_AllowedValuesFuncCalledOnce(t *testing.T) {
+	calls := 0
+	v := envvalidator.New(envvalidator.Field{Key: "REGION_#", Kind: envvalidator.KindEnum, AllowedValuesFunc: func(context.Context) ([]string, error) {
+		calls++
+		return []string{"eu", "us"}, nil
+	}})
+	result, err := v.ValidateMap(context.Background(), map[string]string{"REGION_0": "us", "REGION_1": "eu", "REGION_2": "us"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Strings("REGION"); !reflect.DeepEqual(got, []string{"us", "eu", "us"}) {
+		t.Errorf("unexpected regions: %v", got)
+	}
+	if calls != 1 {
+		t.Errorf("expected AllowedValuesFunc to be called once, got %d calls", calls)
+	}
+	_, err = v.ValidateMap(context.Background(), map[string]string{"REGION_0": "us", "REGION_1": "ap"})
+	var errs envvalidator.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Key != "REGION_1" || !errors.Is(err, envvalidator.ErrNotAllowed) {
+		t.Errorf("expected REGION_1 to be rejected, got %v", err)
+	}
+}
+
+func TestIndexedField_ProcessEnv(t *testing.T) {
+	t.Setenv("IDX_TEST_HOST_0", "a")
+	t.Setenv("IDX_TEST_HOST_1", "b")
+	t.Setenv("IDX_TEST_HOST_3", "d")
+	v := envvalidator.New(envvalidator.Field{Key: "IDX_TEST_HOST_#"})
+	_, err := v.Validate(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "IDX_TEST_HOST_2 is missing") {
+		t.Errorf("expected a gap error from the process environment, got %v", err)
+	}
+	result, err := v.ValidateWith(context.Background(), func(key string) string {
+		return map[string]string{"IDX_TEST_HOST_0": "a", "IDX_TEST_HOST_1": "b"}[key]
+	})
+	if err != nil || !reflect.DeepEqual(result.Strings("IDX_TEST_HOST"), []string{"a", "b"}) {
+		t.Errorf("expected [a b] via ValidateWith, got %v", err)
+	}
+}
+
+func TestIndexedField_ValidateMapStrict(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "ORIGIN_#", Kind: envvalidator.KindURL})
+	_, err := v.ValidateMapStrict(context.Background(), map[string]string{
+		"ORIGIN_0": "https://a.example.com",
+		"ORIGIN_1": "https://b.example.com",
+		"ORIGINS":  "x",
+	})
+	var unknown *envvalidator.UnknownKeysError
+	if !errors.As(err, &unknown) || !reflect.DeepEqual(unknown.Keys, []string{"ORIGINS"}) {
+		t.Errorf("expected only ORIGINS to be reported as undeclared, got %v", err)
+	}
+}
+
+func TestIndexedField_Normalize(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "ORIGIN_#", Kind: envvalidator.KindURL},
+		envvalidator.Field{Key: "TIMEOUT_#", Kind: envvalidator.KindDuration},
+	)
+	got, err := v.Normalize(context.Background(), map[string]string{
+		"APP_ORIGIN_0":  "https://a.example.com",
+		"APP_ORIGIN_1":  "https://b.example.com",
+		"APP_TIMEOUT_0": "2m",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"APP_ORIGIN_0":  "https://a.example.com",
+		"APP_ORIGIN_1":  "https://b.example.com",
+		"APP_TIMEOUT_0": "2m0s",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestIndexedField_WriteShellExports(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ORIGIN_#", Kind: envvalidator.KindURL},
+		envvalidator.Field{Key: "TOKEN_#", Secret: true},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{
+		"ORIGIN_0": "https://a.example.com",
+		"ORIGIN_1": "https://b.example.com",
+		"TOKEN_0":  "s3cr3t",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var b strings.Builder
+	if err := result.WriteShellExports(&b, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "export ORIGIN_0='https://a.example.com'\n" +
+		"export ORIGIN_1='https://b.example.com'\n" +
+		"export TOKEN_0='<redacted>'\n"
+	if b.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, b.String())
+	}
+}
+
+func TestIndexedField_SchemaOutputs(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ORIGIN_#", Kind: envvalidator.KindURL, Required: true, Description: "Allowed origins"},
+		envvalidator.Field{Key: "PORT_#", Kind: envvalidator.KindPort, Default: "80,443"},
+	)
+
+	s := v.Schema()[0]
+	if s.Key != "ORIGIN_#" || s.ResultKey != "ORIGIN" || !s.Indexed || s.Kind != "url" {
+		t.Errorf("unexpected schema: %+v", s)
+	}
+
+	var dotenv strings.Builder
+	if err := v.WriteDotEnv(&dotenv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# Allowed origins\n" +
+		"# kind: url, required: true\n" +
+		"# indexed: ORIGIN_0, ORIGIN_1, ... numbered from 0 without gaps\n" +
+		"ORIGIN_0=\n" +
+		"\n" +
+		"# kind: port, required: false\n" +
+		"# indexed: PORT_0, PORT_1, ... numbered from 0 without gaps\n" +
+		"# default when none is set: 80,443\n" +
+		"PORT_0=\n"
+	if dotenv.String() != want {
+		t.Errorf("unexpected dotenv:\n%s\nwant:\n%s", dotenv.String(), want)
+	}
+
+	var js strings.Builder
+	if err := v.WriteJSONSchema(&js); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc struct {
+		Properties        map[string]any            `json:"properties"`
+		PatternProperties map[string]map[string]any `json:"patternProperties"`
+		Required          []string                  `json:"required"`
+	}
+	if err := json.Unmarshal([]byte(js.String()), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Properties) != 0 {
+		t.Errorf("expected no plain properties, got %v", doc.Properties)
+	}
+	origin := doc.PatternProperties["^ORIGIN_[0-9]+$"]
+	if origin["type"] != "string" || origin["format"] != "uri" {
+		t.Errorf("unexpected ORIGIN pattern property: %v", origin)
+	}
+	port := doc.PatternProperties["^PORT_[0-9]+$"]
+	if _, ok := port["default"]; ok || port["type"] != "integer" {
+		t.Errorf("unexpected PORT pattern property: %v", port)
+	}
+	if !reflect.DeepEqual(doc.Required, []string{"ORIGIN_0"}) {
+		t.Errorf("expected ORIGIN_0 to be required, got %v", doc.Required)
+	}
+}
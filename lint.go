@@ -0,0 +1,236 @@
+package envvalidator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// LintIssue describes a problem with a field declaration, as opposed to a
+// problem with an environment variable's value.
+type LintIssue struct {
+	// Key is the Field.Key of the offending declaration.
+	Key string
+
+	// Message explains what is wrong.
+	Message string
+}
+
+// String returns the issue in "KEY: message" form.
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Key, i.Message)
+}
+
+// Lint checks the field declarations for authoring mistakes that would
+// otherwise only surface at runtime, or not at all. It does not read the
+// environment. Issues are reported in declaration order, and a Validator
+// without issues returns nil.
+//
+// The checks are:
+//   - Required together with a non-empty Default, which makes Required
+//     ineffective because the default always satisfies it;
+//   - an empty Key, or a Key declared more than once;
+//   - a variable name (the Key or Alias, Fallbacks, and DeprecatedKeys)
+//     containing "=" or a control character, which can never be set, or
+//     not of the conventional form [A-Z_][A-Z0-9_]*, such as "DATABASE URL";
+//   - a Kind that is not one of the Kind constants, or KindEnum without
+//     AllowedValues;
+//   - AllowedValues entries that do not parse as the field's Kind;
+//   - a Default that does not parse as the field's Kind or is not one of
+//     its AllowedValues (Transform and Validator.TrimSpace are applied
+//     first, but Validate and ValidateCtx are not called, and ExpandDefault
+//     defaults are skipped because they depend on the environment);
+//   - a Min greater than Max, a MinFloat greater than MaxFloat, a MinDuration
+//     greater than MaxDuration, or a MinLen greater than MaxLen;
+//   - an ExclusiveGroup, ExactlyOneOf, or RequireAnyOf member that is not a
+//     declared key.
+//
+// KindFilePath values are only checked syntactically: MustExist and MustBeDir
+// are ignored, so Lint never touches the filesystem and can run in CI where
+// production paths do not exist.
+//
+// Example:
+//
+//	func TestConfigSchema(t *testing.T) {
+//	    for _, issue := range config.Validator().Lint() {
+//	        t.Error(issue)
+//	    }
+//	}
+func (v *Validator) Lint() []LintIssue {
+	var issues []LintIssue
+	seen := make(map[string]bool, len(v.fields))
+	for _, f := range v.fields {
+		report := func(format string, args ...any) {
+			issues = append(issues, LintIssue{Key: f.Key, Message: fmt.Sprintf(format, args...)})
+		}
+
+		kind := f.Kind
+		if kind == "" {
+			kind = KindString
+		}
+		if f.Key == "" {
+			report("key is empty")
+		} else if seen[f.Key] {
+			report("key is declared more than once")
+		}
+		seen[f.Key] = true
+		for _, name := range v.variableNames(f) {
+			if reason := unsettableName(name); reason != "" {
+				report("variable name %q %s", name, reason)
+			} else if !conventionalName(name) {
+				report("variable name %q is not of the conventional form [A-Z_][A-Z0-9_]*", name)
+			}
+		}
+		if f.Required && f.Default != "" {
+			report("Required has no effect because Default %s is set", displayValue(f, f.Default))
+		}
+		kinds := f.AnyOf
+		if len(kinds) == 0 {
+			kinds = []Kind{kind}
+		}
+		unknown := false
+		for _, k := range kinds {
+			if !knownKind(k) {
+				report("unknown kind %q", k)
+				unknown = true
+			}
+		}
+		if unknown {
+			continue
+		}
+		if kind == KindEnum && len(f.AllowedValues) == 0 && f.AllowedValuesFunc == nil {
+			report("KindEnum requires AllowedValues")
+			continue
+		}
+		for _, allowed := range f.AllowedValues {
+			if _, _, err := parseAnyOf(lintField(f), kind, allowed); err != nil {
+				report("allowed value is invalid: %s", err.Reason)
+			}
+		}
+		if f.Default != "" && !f.ExpandDefault {
+			df, dkind := f, kind
+			if indexed(f) {
+				df, dkind = indexedList(f), KindList
+			}
+			if reason := v.checkDefault(df, dkind); reason != "" {
+				report("default is invalid: %s", reason)
+			}
+		}
+		if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+			report("Min %d is greater than Max %d", *f.Min, *f.Max)
+		}
+		if f.MinFloat != nil && f.MaxFloat != nil && *f.MinFloat > *f.MaxFloat {
+			report("MinFloat %g is greater than MaxFloat %g", *f.MinFloat, *f.MaxFloat)
+		}
+		if f.MinDuration != nil && f.MaxDuration != nil && *f.MinDuration > *f.MaxDuration {
+			report("MinDuration %s is greater than MaxDuration %s", *f.MinDuration, *f.MaxDuration)
+		}
+		if f.MinLen > 0 && f.MaxLen > 0 && f.MinLen > f.MaxLen {
+			report("MinLen %d is greater than MaxLen %d", f.MinLen, f.MaxLen)
+		}
+	}
+	for _, g := range v.groups {
+		for _, key := range g.keys {
+			if !seen[key] {
+				issues = append(issues, LintIssue{Key: key, Message: fmt.Sprintf("group %q references an undeclared key", g.name)})
+			}
+		}
+	}
+	return issues
+}
+
+// checkDefault returns a non-empty reason when f.Default would fail
+// validation on its own merits.
+func (v *Validator) checkDefault(f Field, kind Kind) string {
+	raw := f.Default
+	if v.TrimSpace {
+		raw = strings.TrimSpace(raw)
+	}
+	if f.Transform != nil {
+		raw = f.Transform(raw)
+	}
+	if len(f.AllowedValues) > 0 {
+		found := false
+		for _, allowed := range f.AllowedValues {
+			if raw == allowed || (f.CaseInsensitiveAllowed && strings.EqualFold(raw, allowed)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("value %s is not one of the allowed values", displayValue(f, raw))
+		}
+	}
+	_, kind, err := parseAnyOf(lintField(f), kind, raw)
+	if err != nil {
+		return err.Reason
+	}
+	if re, ok := v.patterns[f.Pattern]; kind == KindString && ok && !re.MatchString(raw) {
+		return fmt.Sprintf("value %s does not match required pattern %q", displayValue(f, raw), f.Pattern)
+	}
+	return ""
+}
+
+// variableNames returns the non-empty names f is read from, for checking
+// their spelling: its variable name, without the "#" of an indexed field,
+// followed by its Fallbacks and DeprecatedKeys.
+func (v *Validator) variableNames(f Field) []string {
+	var names []string
+	for i, name := range v.lookupNames(f) {
+		if i == 0 {
+			name = strings.TrimSuffix(name, "#")
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// unsettableName explains why no environment variable can be named name, or
+// returns "" if one can.
+func unsettableName(name string) string {
+	if strings.Contains(name, "=") {
+		return `contains "=" and can never be set`
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return "contains a control character and can never be set"
+		}
+	}
+	return ""
+}
+
+// conventionalName reports whether name has the conventional POSIX form
+// [A-Z_][A-Z0-9_]*.
+func conventionalName(name string) bool {
+	for i, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// lintField returns a copy of f whose parsing does not consult the
+// filesystem.
+func lintField(f Field) Field {
+	f.MustExist, f.MustBeDir = false, false
+	return f
+}
+
+// knownKind reports whether k is one of the Kind constants.
+func knownKind(k Kind) bool {
+	switch k {
+	case KindString, KindInteger, KindFloat, KindBoolean, KindURL, KindDuration,
+		KindPort, KindEmail, KindJSON, KindCIDR, KindIP, KindList, KindBase64,
+		KindUUID, KindSemver, KindFilePath, KindHex, KindTimezone,
+		KindBytes, KindEnum, KindMap, KindPercentage,
+		KindRegex, KindCron:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package envvalidator_test
+
+import (
+	"testing"
+	"time"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func TestLint(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Required: true, Default: "8080"},
+		envvalidator.Field{Key: "API_KEY", Required: true, Default: "hunter2", Secret: true},
+		envvalidator.Field{Key: "RATIO", Kind: "decimal"},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, AllowedValues: []string{"1", "two", "4"}},
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"debug", "info"}, Default: "info"},
+	)
+	got := v.Lint()
+	want := []string{
+		`PORT: Required has no effect because Default "8080" is set`,
+		`API_KEY: Required has no effect because Default <redacted> is set`,
+		`RATIO: unknown kind "decimal"`,
+		`WORKERS: allowed value is invalid: cannot parse "two" as an integer`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("issue %d: expected %q, got %q", i, want[i], got[i].String())
+		}
+	}
+}
+
+func TestLint_Clean(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+	)
+	if issues := v.Lint(); issues != nil {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestLint_DeclarationConsistency(t *testing.T) {
+	min, max := int64(10), int64(1)
+	lo, hi := 1.0, 0.0
+	short, long := time.Minute, time.Second
+	v := envvalidator.New(
+		envvalidator.Field{Key: ""},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "http"},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort},
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"debug", "info"}, Default: "verbose"},
+		envvalidator.Field{Key: "REGION", Pattern: `^[a-z]+-[0-9]$`, Default: "EU"},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Min: &min, Max: &max},
+		envvalidator.Field{Key: "SAMPLE_RATE", Kind: envvalidator.KindFloat, MinFloat: &lo, MaxFloat: &hi},
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, MinDuration: &short, MaxDuration: &long},
+	)
+	got := v.Lint()
+	want := []string{
+		`: key is empty`,
+		`PORT: default is invalid: cannot parse "http" as a port; must be between 1 and 65535 (0 is not accepted)`,
+		`PORT: key is declared more than once`,
+		`LOG_LEVEL: default is invalid: value "verbose" is not one of the allowed values`,
+		`REGION: default is invalid: value "EU" does not match required pattern "^[a-z]+-[0-9]$"`,
+		`WORKERS: Min 10 is greater than Max 1`,
+		`SAMPLE_RATE: MinFloat 1 is greater than MaxFloat 0`,
+		`TIMEOUT: MinDuration 1m0s is greater than MaxDuration 1s`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("issue %d: expected %q, got %q", i, want[i], got[i].String())
+		}
+	}
+}
+
+func TestLint_FilePathDoesNotTouchFilesystem(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "CERT_DIR", Kind: envvalidator.KindFilePath, MustBeDir: true, Default: "/nonexistent/env-validator/certs"},
+		envvalidator.Field{Key: "CA_FILE", Kind: envvalidator.KindFilePath, MustExist: true, AllowedValues: []string{"/nonexistent/env-validator/ca.pem"}},
+		envvalidator.Field{Key: "KEY_FILE", Kind: envvalidator.KindFilePath, MustExist: true, Default: " "},
+	)
+	got := v.Lint()
+	if len(got) != 1 || got[0].String() != "KEY_FILE: default is invalid: path is empty" {
+		t.Errorf("expected only the empty-path issue, got %v", got)
+	}
+}
+
+func TestLint_EnumWithoutAllowedValues(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "MODE", Kind: envvalidator.KindEnum},
+		envvalidator.Field{Key: "LOG_LEVEL", Kind: envvalidator.KindEnum, AllowedValues: []string{"debug", "info"}, Default: "info"},
+	)
+	issues := v.Lint()
+	if len(issues) != 1 || issues[0].Key != "MODE" || issues[0].Message != "KindEnum requires AllowedValues" {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+}
+
+func TestLint_VariableNames(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "DATABASE URL"},
+		envvalidator.Field{Key: "port"},
+		envvalidator.Field{Key: "A=B"},
+		envvalidator.Field{Key: "databaseURL", Alias: "DATABASE_URL", Fallbacks: []string{"1DB"}},
+		envvalidator.Field{Key: "ORIGIN_#", DeprecatedKeys: []string{"ORIGINS"}},
+	)
+	got := v.Lint()
+	want := []string{
+		`DATABASE URL: variable name "APP_DATABASE URL" is not of the conventional form [A-Z_][A-Z0-9_]*`,
+		`port: variable name "APP_port" is not of the conventional form [A-Z_][A-Z0-9_]*`,
+		`A=B: variable name "APP_A=B" contains "=" and can never be set`,
+		`databaseURL: variable name "1DB" is not of the conventional form [A-Z_][A-Z0-9_]*`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("issue %d: expected %q, got %q", i, want[i], got[i].String())
+		}
+	}
+}
@@ -1,5 +1,14 @@
 package envvalidator
 
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
 // Schema returns a slice of FieldSchema values that describe every declared
 // field in the Validator. The output is deterministic: fields appear in the
 // same order they were passed to New.
@@ -22,18 +31,444 @@ func (v *Validator) Schema() []FieldSchema {
 		if kind == "" {
 			kind = KindString
 		}
+		def := f.Default
+		switch {
+		case f.HideDefault:
+			def = ""
+		case f.Secret && def != "":
+			def = redactedValue
+		}
 		allowed := f.AllowedValues
 		if allowed == nil {
 			allowed = []string{}
 		}
+		var anyOf []string
+		for _, k := range f.AnyOf {
+			anyOf = append(anyOf, string(k))
+		}
+		accessKey := ""
+		if key := resultKey(f); key != v.envKey(f) {
+			accessKey = key
+		}
 		out[i] = FieldSchema{
-			Key:           f.Key,
-			Kind:          string(kind),
-			Required:      f.Required,
-			Default:       f.Default,
-			Description:   f.Description,
-			AllowedValues: allowed,
+			Key:                    v.envKey(f),
+			ResultKey:              accessKey,
+			Kind:                   string(kind),
+			AnyOf:                  anyOf,
+			Required:               f.Required,
+			Default:                def,
+			Description:            f.Description,
+			Group:                  f.Group,
+			AllowedValues:          allowed,
+			CaseInsensitiveAllowed: f.CaseInsensitiveAllowed,
+			Pattern:                f.Pattern,
+			Secret:                 f.Secret,
+			Deprecated:             f.Deprecated,
+			Min:                    f.Min,
+			Max:                    f.Max,
+			MinFloat:               f.MinFloat,
+			MaxFloat:               f.MaxFloat,
+			MinDuration:            durationString(f.MinDuration),
+			MaxDuration:            durationString(f.MaxDuration),
+			MinLen:                 f.MinLen,
+			MaxLen:                 f.MaxLen,
+			Indexed:                indexed(f),
+		}
+	}
+	return out
+}
+
+// Fields returns a copy of the declared fields, in declaration order. Unlike
+// Schema it includes everything, such as Validate hooks and Transform
+// functions, for tooling that needs the full declarations. Slices, maps, and
+// bound pointers are copied too, so modifying the result does not affect v.
+//
+// Example:
+//
+//	for _, f := range v.Fields() {
+//	    fmt.Println(f.Key, f.Kind, f.Validate != nil)
+//	}
+func (v *Validator) Fields() []Field {
+	out := make([]Field, len(v.fields))
+	for i, f := range v.fields {
+		f.Fallbacks = append([]string(nil), f.Fallbacks...)
+		f.DeprecatedKeys = append([]string(nil), f.DeprecatedKeys...)
+		f.AllowedValues = append([]string(nil), f.AllowedValues...)
+		f.WeakValues = append([]string(nil), f.WeakValues...)
+		f.AnyOf = append([]Kind(nil), f.AnyOf...)
+		f.Min, f.Max = copyPtr(f.Min), copyPtr(f.Max)
+		f.MinFloat, f.MaxFloat = copyPtr(f.MinFloat), copyPtr(f.MaxFloat)
+		f.MinDuration, f.MaxDuration = copyPtr(f.MinDuration), copyPtr(f.MaxDuration)
+		if f.BoolTokens != nil {
+			tokens := make(map[string]bool, len(f.BoolTokens))
+			for k, b := range f.BoolTokens {
+				tokens[k] = b
+			}
+			f.BoolTokens = tokens
 		}
+		out[i] = f
 	}
 	return out
 }
+
+// durationString formats *d for FieldSchema, or returns "" if d is nil.
+func durationString(d *time.Duration) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+// copyPtr returns a pointer to a copy of *p, or nil if p is nil.
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// WriteDotEnv writes a template .env file describing every declared field, in
+// declaration order. Each variable is preceded by comments holding its
+// Description, Kind, requiredness, and AllowedValues, and is assigned its
+// Default (or nothing). Secret fields are always written with an empty value
+// and a "# secret" note. An indexed field such as "ORIGIN_#" is written as
+// ORIGIN_0 with an empty value and a comment describing the numbering, and
+// its Default, a delimited list, is shown in a comment.
+//
+// The output can be read back with LoadDotEnv.
+//
+// Example:
+//
+//	f, _ := os.Create(".env.example")
+//	defer f.Close()
+//	if err := v.WriteDotEnv(f); err != nil {
+//	    log.Fatal(err)
+//	}
+func (v *Validator) WriteDotEnv(w io.Writer) error {
+	var b strings.Builder
+	for i, fs := range v.Schema() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if fs.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", fs.Description)
+		}
+		fmt.Fprintf(&b, "# kind: %s, required: %t\n", fs.Kind, fs.Required)
+		if len(fs.AllowedValues) > 0 {
+			fmt.Fprintf(&b, "# allowed: %s\n", strings.Join(fs.AllowedValues, ", "))
+		}
+		value := fs.Default
+		if fs.Secret {
+			b.WriteString("# secret\n")
+			value = ""
+		}
+		name := fs.Key
+		if fs.Indexed {
+			base := strings.TrimSuffix(fs.Key, "#")
+			fmt.Fprintf(&b, "# indexed: %s0, %s1, ... numbered from 0 without gaps\n", base, base)
+			if value != "" {
+				fmt.Fprintf(&b, "# default when none is set: %s\n", value)
+			}
+			name, value = base+"0", ""
+		}
+		fmt.Fprintf(&b, "%s=%s\n", name, dotEnvQuote(value))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotEnvQuote wraps value in double quotes when it contains characters that
+// would otherwise be trimmed or misread by a dotenv parser.
+func dotEnvQuote(value string) string {
+	if value == "" || !strings.ContainsAny(value, " \t#'\"") {
+		return value
+	}
+	if strings.Contains(value, "\"") {
+		return "'" + value + "'"
+	}
+	return "\"" + value + "\""
+}
+
+// WriteMarkdown writes the schema as a Markdown table with the columns Key,
+// Kind, Required, Default, Allowed Values, and Description, one row per field
+// in declaration order. Pipe characters and newlines inside cells are
+// escaped so the table renders correctly. When any field sets Group, one
+// table is written per group under a "### Group" heading, with groups in the
+// order they first appear and fields without a group under "### Other".
+//
+// Example:
+//
+//	var b strings.Builder
+//	v.WriteMarkdown(&b)
+//	// | Key | Kind | Required | Default | Allowed Values | Description |
+//	// |-----|------|----------|---------|----------------|-------------|
+//	// | `PORT` | integer | no | `8080` |  | HTTP server port |
+func (v *Validator) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	sections := schemaSections(v.Schema())
+	for i, section := range sections {
+		if len(sections) > 1 || section.name != "" {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			name := section.name
+			if name == "" {
+				name = "Other"
+			}
+			fmt.Fprintf(&b, "### %s\n\n", markdownEscape(name))
+		}
+		writeMarkdownTable(&b, section.fields)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// schemaSection is the fields of one Field.Group, in declaration order.
+type schemaSection struct {
+	name   string
+	fields []FieldSchema
+}
+
+// schemaSections splits schema by group, with groups in first-seen order.
+func schemaSections(schema []FieldSchema) []schemaSection {
+	var sections []schemaSection
+	index := make(map[string]int)
+	for _, fs := range schema {
+		i, ok := index[fs.Group]
+		if !ok {
+			i = len(sections)
+			index[fs.Group] = i
+			sections = append(sections, schemaSection{name: fs.Group})
+		}
+		sections[i].fields = append(sections[i].fields, fs)
+	}
+	return sections
+}
+
+// writeMarkdownTable writes the table header and one row per field to b.
+func writeMarkdownTable(b *strings.Builder, fields []FieldSchema) {
+	b.WriteString("| Key | Kind | Required | Default | Allowed Values | Description |\n")
+	b.WriteString("|-----|------|----------|---------|----------------|-------------|\n")
+	for _, fs := range fields {
+		required := "no"
+		if fs.Required {
+			required = "yes"
+		}
+		allowed := make([]string, len(fs.AllowedValues))
+		for i, a := range fs.AllowedValues {
+			allowed[i] = markdownCode(a)
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCode(fs.Key),
+			fs.Kind,
+			required,
+			markdownCode(fs.Default),
+			strings.Join(allowed, ", "),
+			markdownEscape(fs.Description),
+		)
+	}
+}
+
+// markdownEscape makes s safe to place inside a Markdown table cell.
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// markdownCode renders s as inline code inside a table cell, or an empty
+// string when s is empty.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + markdownEscape(s) + "`"
+}
+
+// jsonSchemaDraft is the $schema URI written by WriteJSONSchema.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// WriteJSONSchema writes a JSON Schema (draft 2020-12) document describing the
+// declared fields as the properties of a single object, keyed by variable
+// name. Integer and port fields become "integer", floats "number", booleans
+// "boolean", and lists "array" of their element type; everything else is a
+// "string", with a format for URL, email, UUID, and single-family IP fields.
+// AllowedValues become "enum", bounds become "minimum"/"maximum", Pattern
+// becomes "pattern", MinLen and MaxLen become "minLength"/"maxLength", and
+// required fields are listed in "required". Secret fields are marked
+// "writeOnly" and their defaults are omitted. Because JSON object members are
+// unordered, a field's Group is recorded in an "x-group" annotation rather
+// than by position. An indexed field such as "ORIGIN_#" is described under
+// "patternProperties" as "^ORIGIN_[0-9]+$" with the schema of one element,
+// and when required, ORIGIN_0 is listed in "required".
+//
+// Example:
+//
+//	f, _ := os.Create("config.schema.json")
+//	defer f.Close()
+//	if err := v.WriteJSONSchema(f); err != nil {
+//	    log.Fatal(err)
+//	}
+func (v *Validator) WriteJSONSchema(w io.Writer) error {
+	doc := struct {
+		Schema            string                    `json:"$schema"`
+		Type              string                    `json:"type"`
+		Properties        map[string]map[string]any `json:"properties"`
+		PatternProperties map[string]map[string]any `json:"patternProperties,omitempty"`
+		Required          []string                  `json:"required,omitempty"`
+	}{
+		Schema:     jsonSchemaDraft,
+		Type:       "object",
+		Properties: make(map[string]map[string]any, len(v.fields)),
+	}
+	for _, f := range v.fields {
+		name := v.envKey(f)
+		if indexed(f) {
+			base := strings.TrimSuffix(name, "#")
+			if doc.PatternProperties == nil {
+				doc.PatternProperties = make(map[string]map[string]any)
+			}
+			prop := jsonSchemaProperty(f)
+			// The default is a delimited list, not the value of one element.
+			delete(prop, "default")
+			doc.PatternProperties["^"+regexp.QuoteMeta(base)+"[0-9]+$"] = prop
+			if f.Required {
+				doc.Required = append(doc.Required, base+"0")
+			}
+			continue
+		}
+		doc.Properties[name] = jsonSchemaProperty(f)
+		if f.Required {
+			doc.Required = append(doc.Required, name)
+		}
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// jsonSchemaProperty builds the JSON Schema for a single field.
+func jsonSchemaProperty(f Field) map[string]any {
+	kind := f.Kind
+	if kind == "" {
+		kind = KindString
+	}
+	prop := map[string]any{}
+	if f.Description != "" {
+		prop["description"] = f.Description
+	}
+	if f.Deprecated != "" {
+		prop["deprecated"] = true
+	}
+	if f.Group != "" {
+		prop["x-group"] = f.Group
+	}
+	if f.Secret {
+		prop["writeOnly"] = true
+	}
+
+	target := prop
+	if kind == KindList {
+		prop["type"] = "array"
+		items := map[string]any{}
+		prop["items"] = items
+		target = items
+		kind = f.ElementKind
+		if kind == "" {
+			kind = KindString
+		}
+	}
+	if kind == KindMap {
+		prop["type"] = "object"
+		values := map[string]any{}
+		prop["additionalProperties"] = values
+		target = values
+		kind = f.ElementKind
+		if kind == "" {
+			kind = KindString
+		}
+	}
+	switch kind {
+	case KindInteger, KindPort:
+		target["type"] = "integer"
+	case KindFloat:
+		target["type"] = "number"
+	case KindBoolean:
+		target["type"] = "boolean"
+	case KindJSON:
+		// Any JSON value is accepted, so no type is declared.
+	default:
+		target["type"] = "string"
+	}
+	switch {
+	case kind == KindURL:
+		target["format"] = "uri"
+	case kind == KindEmail:
+		target["format"] = "email"
+	case kind == KindUUID:
+		target["format"] = "uuid"
+	case kind == KindIP && f.IPVersion == 4:
+		target["format"] = "ipv4"
+	case kind == KindIP && f.IPVersion == 6:
+		target["format"] = "ipv6"
+	}
+
+	switch {
+	case kind == KindPort:
+		target["minimum"], target["maximum"] = 1, 65535
+	case kind == KindInteger:
+		if f.Min != nil {
+			target["minimum"] = *f.Min
+		}
+		if f.Max != nil {
+			target["maximum"] = *f.Max
+		}
+	case kind == KindFloat:
+		if f.MinFloat != nil {
+			target["minimum"] = *f.MinFloat
+		}
+		if f.MaxFloat != nil {
+			target["maximum"] = *f.MaxFloat
+		}
+	case kind == KindString:
+		if f.Pattern != "" {
+			target["pattern"] = f.Pattern
+		}
+		if f.MinLen > 0 {
+			target["minLength"] = f.MinLen
+		}
+		if f.MaxLen > 0 {
+			target["maxLength"] = f.MaxLen
+		}
+	}
+	if len(f.AllowedValues) > 0 {
+		enum := make([]any, len(f.AllowedValues))
+		for i, allowed := range f.AllowedValues {
+			enum[i] = jsonSchemaValue(f, kind, allowed)
+		}
+		target["enum"] = enum
+	}
+	if f.Default != "" && !f.Secret && !f.HideDefault {
+		fieldKind := KindString
+		if f.Kind != "" {
+			fieldKind = f.Kind
+		}
+		prop["default"] = jsonSchemaValue(f, fieldKind, f.Default)
+	}
+	return prop
+}
+
+// jsonSchemaValue converts raw to the JSON type used for kind in the schema.
+// Values that do not parse are kept as strings.
+func jsonSchemaValue(f Field, kind Kind, raw string) any {
+	switch kind {
+	case KindInteger, KindPort, KindFloat, KindBoolean, KindList:
+		if parsed, err := parseValue(f, kind, raw); err == nil {
+			return jsonFriendly(parsed)
+		}
+	}
+	return raw
+}
@@ -0,0 +1,256 @@
+package envvalidator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	envvalidator "github.com/njchilds90/go-env-validator"
+)
+
+func TestWriteDotEnv(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Default: "8080", Description: "HTTP server port"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true, Secret: true, Default: "postgres://dev"},
+		envvalidator.Field{Key: "LOG_LEVEL", Default: "info", AllowedValues: []string{"debug", "info"}},
+		envvalidator.Field{Key: "GREETING", Default: "hello world"},
+	)
+	var b strings.Builder
+	if err := v.WriteDotEnv(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `# HTTP server port
+# kind: integer, required: false
+PORT=8080
+
+# kind: url, required: true
+# secret
+DATABASE_URL=
+
+# kind: string, required: false
+# allowed: debug, info
+LOG_LEVEL=info
+
+# kind: string, required: false
+GREETING="hello world"
+`
+	if b.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+func TestWriteDotEnv_RoundTrips(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "GREETING", Default: "hello # world"},
+		envvalidator.Field{Key: "QUOTED", Default: `say "hi"`},
+	)
+	var b strings.Builder
+	if err := v.WriteDotEnv(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env, err := envvalidator.LoadDotEnv(writeFile(t, ".env.example", b.String()))
+	if err != nil {
+		t.Fatalf("unexpected error reading template: %v", err)
+	}
+	if env["GREETING"] != "hello # world" || env["QUOTED"] != `say "hi"` {
+		t.Errorf("template did not round-trip: %v", env)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Default: "8080", Description: "HTTP server port"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true, Description: "Postgres URL | primary"},
+		envvalidator.Field{Key: "LOG_LEVEL", Default: "info", AllowedValues: []string{"debug", "info"}, Description: "Logging\nverbosity"},
+	)
+	var b strings.Builder
+	if err := v.WriteMarkdown(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "| Key | Kind | Required | Default | Allowed Values | Description |\n" +
+		"|-----|------|----------|---------|----------------|-------------|\n" +
+		"| `PORT` | integer | no | `8080` |  | HTTP server port |\n" +
+		"| `DATABASE_URL` | url | yes |  |  | Postgres URL \\| primary |\n" +
+		"| `LOG_LEVEL` | string | no | `info` | `debug`, `info` | Logging verbosity |\n"
+	if b.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+func TestWriteJSONSchema(t *testing.T) {
+	min := int64(1)
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080", Description: "HTTP server port"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true, Secret: true, Default: "postgres://dev"},
+		envvalidator.Field{Key: "LOG_LEVEL", Default: "info", AllowedValues: []string{"debug", "info"}},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Min: &min, AllowedValues: []string{"1", "2"}},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean, Default: "yes"},
+		envvalidator.Field{Key: "TIMEOUTS", Kind: envvalidator.KindList, ElementKind: envvalidator.KindDuration, Default: "1s,2m"},
+	)
+	var b strings.Builder
+	if err := v.WriteJSONSchema(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "DATABASE_URL": {
+      "format": "uri",
+      "type": "string",
+      "writeOnly": true
+    },
+    "DEBUG": {
+      "default": true,
+      "type": "boolean"
+    },
+    "LOG_LEVEL": {
+      "default": "info",
+      "enum": [
+        "debug",
+        "info"
+      ],
+      "type": "string"
+    },
+    "PORT": {
+      "default": 8080,
+      "description": "HTTP server port",
+      "maximum": 65535,
+      "minimum": 1,
+      "type": "integer"
+    },
+    "TIMEOUTS": {
+      "default": [
+        "1s",
+        "2m0s"
+      ],
+      "items": {
+        "type": "string"
+      },
+      "type": "array"
+    },
+    "WORKERS": {
+      "enum": [
+        1,
+        2
+      ],
+      "minimum": 1,
+      "type": "integer"
+    }
+  },
+  "required": [
+    "DATABASE_URL"
+  ]
+}
+`
+	if b.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+func TestFields(t *testing.T) {
+	max := int64(10)
+	validate := func(string) error { return nil }
+	v := envvalidator.New(
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Max: &max, Validate: validate},
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"debug", "info"}},
+	)
+	fields := v.Fields()
+	if len(fields) != 2 || fields[0].Key != "WORKERS" || fields[1].Key != "LOG_LEVEL" {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+	if fields[0].Validate == nil || *fields[0].Max != 10 {
+		t.Errorf("expected full declaration, got %+v", fields[0])
+	}
+
+	fields[0].Key = "CHANGED"
+	*fields[0].Max = 99
+	fields[1].AllowedValues[0] = "trace"
+	again := v.Fields()
+	if again[0].Key != "WORKERS" || *again[0].Max != 10 || again[1].AllowedValues[0] != "debug" {
+		t.Errorf("modifying the copy affected the validator: %+v", again)
+	}
+}
+
+func TestWriteJSONSchema_MapKind(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "LIMITS", Kind: envvalidator.KindMap, ElementKind: envvalidator.KindInteger})
+	var b strings.Builder
+	if err := v.WriteJSONSchema(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `"LIMITS": {
+      "additionalProperties": {
+        "type": "integer"
+      },
+      "type": "object"
+    }`
+	if !strings.Contains(b.String(), expected) {
+		t.Errorf("expected map schema:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func TestSchema_HideDefault(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "CACHE_SIZE", Kind: envvalidator.KindInteger, Default: "512", HideDefault: true},
+		envvalidator.Field{Key: "API_KEY", Default: "dev-key", Secret: true, HideDefault: true},
+	)
+	for _, fs := range v.Schema() {
+		if fs.Default != "" {
+			t.Errorf("%s: expected Default to be omitted, got %q", fs.Key, fs.Default)
+		}
+	}
+	var md, schema strings.Builder
+	if err := v.WriteMarkdown(&md); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := v.WriteJSONSchema(&schema); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(md.String(), "512") || strings.Contains(schema.String(), "512") {
+		t.Errorf("expected hidden default to be absent:\n%s\n%s", md.String(), schema.String())
+	}
+
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Integer("CACHE_SIZE") != 512 || result.Redacted()["CACHE_SIZE"] != int64(512) {
+		t.Errorf("expected hidden default to still apply unmasked, got %v", result.Redacted())
+	}
+}
+
+func TestWriteMarkdown_Groups(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true, Group: "Database"},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080", Group: "HTTP"},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean, Default: "false"},
+		envvalidator.Field{Key: "DB_POOL", Kind: envvalidator.KindInteger, Default: "10", Group: "Database"},
+	)
+	var b strings.Builder
+	if err := v.WriteMarkdown(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	header := "| Key | Kind | Required | Default | Allowed Values | Description |\n" +
+		"|-----|------|----------|---------|----------------|-------------|\n"
+	expected := "### Database\n\n" + header +
+		"| `DATABASE_URL` | url | yes |  |  |  |\n" +
+		"| `DB_POOL` | integer | no | `10` |  |  |\n" +
+		"\n### HTTP\n\n" + header +
+		"| `PORT` | port | no | `8080` |  |  |\n" +
+		"\n### Other\n\n" + header +
+		"| `DEBUG` | boolean | no | `false` |  |  |\n"
+	if b.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+	if v.Schema()[0].Group != "Database" {
+		t.Errorf("expected group in schema, got %q", v.Schema()[0].Group)
+	}
+
+	var schema strings.Builder
+	if err := v.WriteJSONSchema(&schema); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(schema.String(), `"x-group": "HTTP"`) {
+		t.Errorf("expected x-group annotation, got:\n%s", schema.String())
+	}
+}
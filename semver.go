@@ -0,0 +1,101 @@
+package envvalidator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SemanticVersion is the parsed form of a KindSemver value.
+type SemanticVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+// String returns the version in semver syntax, for example "1.2.3-rc.1+build".
+func (sv SemanticVersion) String() string {
+	s := fmt.Sprintf("%d.%d.%d", sv.Major, sv.Minor, sv.Patch)
+	if sv.Prerelease != "" {
+		s += "-" + sv.Prerelease
+	}
+	if sv.Build != "" {
+		s += "+" + sv.Build
+	}
+	return s
+}
+
+// parseSemver parses s as a semantic version. When allowPartial is true the
+// minor and patch components may be omitted and default to zero. The
+// returned problem describes which part failed without echoing the input.
+func parseSemver(s string, allowPartial bool) (SemanticVersion, string) {
+	var sv SemanticVersion
+	if s == "" {
+		return sv, "empty version"
+	}
+	core, build, hasBuild := strings.Cut(s, "+")
+	if hasBuild {
+		if problem := checkIdentifiers(build, false); problem != "" {
+			return sv, "invalid build metadata: " + problem
+		}
+		sv.Build = build
+	}
+	core, pre, hasPre := strings.Cut(core, "-")
+	if hasPre {
+		if problem := checkIdentifiers(pre, true); problem != "" {
+			return sv, "invalid pre-release: " + problem
+		}
+		sv.Prerelease = pre
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 || (len(parts) < 3 && !allowPartial) {
+		return sv, "expected MAJOR.MINOR.PATCH"
+	}
+	names := []string{"major", "minor", "patch"}
+	nums := []*int{&sv.Major, &sv.Minor, &sv.Patch}
+	for i, part := range parts {
+		n, ok := parseVersionNumber(part)
+		if !ok {
+			return sv, fmt.Sprintf("invalid %s version", names[i])
+		}
+		*nums[i] = n
+	}
+	return sv, ""
+}
+
+// parseVersionNumber parses a numeric version component, rejecting signs and
+// leading zeros as the semver specification requires.
+func parseVersionNumber(s string) (int, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') || strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// checkIdentifiers validates dot-separated pre-release or build identifiers.
+// Numeric pre-release identifiers may not have leading zeros.
+func checkIdentifiers(s string, numericNoLeadingZero bool) string {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return "empty identifier"
+		}
+		numeric := true
+		for _, r := range id {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				numeric = false
+			default:
+				return "identifiers may only contain [0-9A-Za-z-]"
+			}
+		}
+		if numericNoLeadingZero && numeric && len(id) > 1 && id[0] == '0' {
+			return "numeric identifier has a leading zero"
+		}
+	}
+	return ""
+}
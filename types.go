@@ -6,7 +6,24 @@
 // configuration errors with clear, structured error messages.
 package envvalidator
 
-import "fmt"
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Kind represents the expected data type of an environment variable.
 type Kind string
@@ -18,10 +35,12 @@ const (
 	// KindInteger expects a base-10 integer value.
 	KindInteger Kind = "integer"
 
-	// KindFloat expects a decimal number value.
+	// KindFloat expects a finite decimal number value. NaN and infinities
+	// are always rejected.
 	KindFloat Kind = "float"
 
-	// KindBoolean expects one of: true, false, 1, 0, yes, no (case-insensitive).
+	// KindBoolean expects one of: true, false, 1, 0, yes, no (case-insensitive),
+	// or one of the field's BoolTokens.
 	KindBoolean Kind = "boolean"
 
 	// KindURL expects a value that is a valid absolute URL with a scheme and host.
@@ -29,6 +48,93 @@ const (
 
 	// KindDuration expects a Go duration string such as "5s", "1m30s", or "2h".
 	KindDuration Kind = "duration"
+
+	// KindPort expects a TCP/UDP port number between 1 and 65535. Port 0, the
+	// "any available port" convention, is rejected.
+	KindPort Kind = "port"
+
+	// KindEmail expects a single email address such as "ops@example.com".
+	// Display names and comma-separated lists are rejected.
+	KindEmail Kind = "email"
+
+	// KindJSON expects a well-formed JSON document. The decoded value is stored
+	// using the encoding/json conventions for an any target.
+	KindJSON Kind = "json"
+
+	// KindCIDR expects a network range in CIDR notation such as "10.0.0.0/8".
+	KindCIDR Kind = "cidr"
+
+	// KindIP expects a single IPv4 or IPv6 address. Use Field.IPVersion to
+	// restrict the accepted family.
+	KindIP Kind = "ip"
+
+	// KindList expects a delimiter-separated list such as "a.com,b.com". Each
+	// element is parsed as Field.ElementKind, which defaults to KindString.
+	KindList Kind = "list"
+
+	// KindBase64 expects base64-encoded data, stored as the decoded bytes. The
+	// standard alphabet is used unless Field.Base64URL is set.
+	KindBase64 Kind = "base64"
+
+	// KindUUID expects a UUID in the canonical 8-4-4-4-12 hex form, in any
+	// case. The value is stored in lowercase.
+	KindUUID Kind = "uuid"
+
+	// KindSemver expects a semantic version such as "1.2.3" or
+	// "1.2.3-rc.1+build". Set Field.AllowPartialVersion to accept "1.2" or "1".
+	KindSemver Kind = "semver"
+
+	// KindFilePath expects a filesystem path, stored as a cleaned absolute
+	// path. The filesystem is only consulted when Field.MustExist or
+	// Field.MustBeDir is set.
+	KindFilePath Kind = "filepath"
+
+	// KindHex expects an even-length hexadecimal string in either case,
+	// stored as the decoded bytes. Set Field.ByteLength to require an exact
+	// decoded size.
+	KindHex Kind = "hex"
+
+	// KindTimezone expects an IANA time zone name such as "America/New_York",
+	// loaded with time.LoadLocation and stored as a *time.Location. Loading
+	// depends on the system tz database or an embedded time/tzdata package.
+	KindTimezone Kind = "timezone"
+
+	// KindBytes expects a non-negative byte size such as "512", "10MB", or
+	// "2GiB", stored as an int64 number of bytes. Units are case-insensitive;
+	// KB, MB, GB, and TB are powers of 1000 and KiB, MiB, GiB, and TiB powers
+	// of 1024. Min and Max, if set, bound the size in bytes.
+	KindBytes Kind = "bytes"
+
+	// KindEnum expects one of Field.AllowedValues, which must be non-empty,
+	// stored as the matching allowed value. It behaves like KindString with
+	// AllowedValues but declares the intent in Schema and JSON Schema output.
+	KindEnum Kind = "enum"
+
+	// KindMap expects delimiter-separated key=value entries such as
+	// "env=prod;team=payments". Entries are separated by Field.Delimiter
+	// (default ";") and keys from values by Field.KeyValueSeparator (default
+	// "="). Keys and values are trimmed, each value is parsed as
+	// Field.ElementKind (default KindString), and a duplicate key is an
+	// error. The result is a map[string]string for string-valued elements.
+	KindMap Kind = "map"
+
+	// KindPercentage expects a number from 0 to 100, optionally followed by
+	// "%", so "85" and "85%" are equivalent. It is stored as a float64 on the
+	// same 0-100 scale, or as a fraction from 0 to 1 when
+	// Field.PercentAsFraction is set.
+	KindPercentage Kind = "percentage"
+
+	// KindRegex expects a regular expression in RE2 syntax, compiled with
+	// regexp.Compile and stored as a *regexp.Regexp, so a bad pattern fails
+	// at startup instead of on first use.
+	KindRegex Kind = "regex"
+
+	// KindCron expects a standard five-field cron expression such as
+	// "0 2 * * *" (minute, hour, day of month, month, day of week), stored as
+	// a CronSchedule. Fields accept *, numbers, ranges, steps, comma lists,
+	// and three-letter month and weekday names. Set Field.CronSeconds to also
+	// accept a six-field expression with a leading seconds field.
+	KindCron Kind = "cron"
 )
 
 // Field describes a single expected environment variable: its key, type,
@@ -36,46 +142,354 @@ const (
 // description used in schema output and error messages.
 type Field struct {
 	// Key is the exact environment variable name, for example "DATABASE_URL".
+	// It is also the key used to read the value from a Result.
+	//
+	// A Key ending in "#", such as "ORIGIN_#", declares an indexed field: the
+	// variables ORIGIN_0, ORIGIN_1, and so on are each validated as Kind and
+	// collected into a list stored under "ORIGIN", as a KindList of that
+	// ElementKind would be. The indexes must run from 0 without gaps. When
+	// none is set, Default and Fallbacks are read as a delimited list.
 	Key string
 
+	// Alias, if set, is the environment variable name to look up instead of
+	// Key. Result accessors still use Key, decoupling the Go-side identifier
+	// from the variable name, for example Key "databaseURL" with Alias
+	// "DATABASE_URL".
+	Alias string
+
 	// Kind is the expected data type. Defaults to KindString if not set.
 	Kind Kind
 
+	// AnyOf, if non-empty, accepts a value that parses as any of the listed
+	// kinds, tried in order, and Kind is then ignored. The first kind that
+	// succeeds determines the stored value; Result.Kind reports which one.
+	AnyOf []Kind
+
 	// Required marks this variable as mandatory. Validation fails if it is
 	// absent and no Default is provided.
 	Required bool
 
+	// AllowEmpty treats a variable that is explicitly set to the empty string
+	// as provided rather than absent. The empty value then satisfies
+	// Required and RequiredIf, Default is not applied, and the value is
+	// validated as usual, so for kinds other than KindString and KindEnum it
+	// must still parse. A variable that is not set at all still falls back to
+	// Default or fails Required.
+	AllowEmpty bool
+
+	// RequiredIn lists the validation profiles, such as "prod", in which the
+	// variable is mandatory. It applies only to Validator.ValidateProfile
+	// with one of these profiles; otherwise Required alone decides.
+	RequiredIn []string
+
+	// RequiredIf, if set, makes the variable mandatory whenever it returns
+	// true. It receives the full input map (the map given to ValidateMap, or
+	// the variables read by Validate) and is evaluated before any field is
+	// parsed, so it sees raw strings rather than typed values.
+	RequiredIf func(env map[string]string) bool
+
+	// RequiredWhen describes the RequiredIf condition for error messages, for
+	// example "AUTH_MODE=oauth".
+	RequiredWhen string
+
+	// Fallbacks lists other variable names consulted, in order, when Key is
+	// absent or empty, before falling back to Default. Fallback names are used
+	// exactly as written and are not prefixed.
+	Fallbacks []string
+
+	// DeprecatedKeys lists former names of the variable, consulted in order
+	// after Key and Fallbacks. When one of them supplies the value, a warning
+	// naming the replacement is reported through Validator.OnWarning and
+	// Result.Warnings. Like Key, they are looked up under the Validator's
+	// prefix.
+	DeprecatedKeys []string
+
 	// Default is the value used when the variable is absent and Required is
 	// false. It must be a string representation of the correct Kind.
 	Default string
 
+	// DefaultFunc, if set, computes the default when the variable is absent,
+	// for defaults that depend on other variables, such as "json" when
+	// ENV=prod. Like RequiredIf it receives the raw input map. A non-empty
+	// result takes precedence over Default; an empty one falls back to it.
+	// The precedence is therefore: provided value, DefaultFunc, Default.
+	// ExpandDefault applies only to Default.
+	DefaultFunc func(env map[string]string) string
+
 	// Description is a human-readable explanation of what this variable does.
 	// It appears in schema output and validation error messages.
 	Description string
 
+	// Group, if set, names the documentation section the variable belongs
+	// to, such as "Database" or "HTTP". WriteMarkdown renders one table per
+	// group and WriteJSONSchema annotates each property with "x-group". It
+	// does not affect validation.
+	Group string
+
 	// AllowedValues, if non-empty, restricts the value to one of the listed
-	// strings. The comparison is case-sensitive.
+	// strings. The comparison is case-sensitive unless CaseInsensitiveAllowed
+	// is set.
 	AllowedValues []string
+
+	// AllowedValuesFunc, if set, loads the allowed values at validation time,
+	// for sets kept in another service. It is called once per validation,
+	// after the value is resolved, and its result replaces AllowedValues; an
+	// indexed field calls it once for all of its variables.
+	// An error, including ctx being cancelled, fails the field; an empty
+	// result allows any value, as an empty AllowedValues does. Lint and
+	// Schema do not call it.
+	AllowedValuesFunc func(ctx context.Context) ([]string, error)
+
+	// CaseInsensitiveAllowed compares the value to AllowedValues using
+	// strings.EqualFold. A matching value is replaced by its canonical form
+	// from AllowedValues, so "INFO" is stored as "info" when "info" is allowed.
+	CaseInsensitiveAllowed bool
+
+	// Secret marks the value as sensitive. A secret value is never echoed in
+	// validation errors, its Default is masked in Schema output, and it is
+	// masked in Result.Redacted.
+	Secret bool
+
+	// HideDefault omits Default from documentation output (Schema,
+	// WriteDotEnv, WriteMarkdown, and WriteJSONSchema) without affecting
+	// validation, which still applies the default. Unlike Secret it does not
+	// mask the runtime value in errors or Result dumps. A Secret field's
+	// Default is masked in documentation regardless; HideDefault leaves it
+	// out altogether.
+	HideDefault bool
+
+	// Pattern, if non-empty, is a regular expression that a KindString value
+	// must match. It is compiled once when the Validator is created; use
+	// NewStrict to reject an invalid pattern at construction time.
+	Pattern string
+
+	// IPVersion restricts a KindIP value to IPv4 (4) or IPv6 (6). Zero accepts
+	// either family.
+	IPVersion int
+
+	// Delimiter separates the elements of a KindList value, defaulting to
+	// ",", or the entries of a KindMap value, defaulting to ";".
+	Delimiter string
+
+	// PercentAsFraction stores a KindPercentage value as a fraction from 0
+	// to 1 instead of on the 0-100 scale, so "85%" is stored as 0.85.
+	PercentAsFraction bool
+
+	// KeyValueSeparator separates the key from the value in each KindMap
+	// entry. Defaults to "=".
+	KeyValueSeparator string
+
+	// ElementKind is the Kind each KindList element or KindMap value is
+	// parsed as. Defaults to KindString. Field constraints such as Min and
+	// Max apply to each element.
+	ElementKind Kind
+
+	// DropEmpty discards empty KindList elements or KindMap entries, such as
+	// those produced by a trailing delimiter. When false, an empty element is
+	// a validation error.
+	DropEmpty bool
+
+	// TrimElements removes leading and trailing whitespace from each KindList
+	// element before it is validated as ElementKind, so "a, b" yields "a"
+	// and "b" and constraints such as MinLen see the trimmed element.
+	// Non-string element kinds already ignore surrounding whitespace when
+	// parsing; this extends that to string elements, which are otherwise
+	// kept as written. A whitespace-only element counts as empty.
+	TrimElements bool
+
+	// Transform, if set, rewrites the raw value before any further checks. It
+	// runs after the value is looked up (or Default applied) and after
+	// Validator.TrimSpace, but before AllowedValues, Kind parsing, Pattern,
+	// and Validate.
+	Transform func(raw string) string
+
+	// Validate, if set, applies an application-specific check to the value.
+	// It runs last, after AllowedValues, the built-in Kind parsing, and
+	// Pattern have all succeeded, and receives the raw string (the Default
+	// when the variable was absent). A non-nil error fails the field with the
+	// error's message as the reason; the message should not echo the value of
+	// a Secret field.
+	Validate func(raw string) error
+
+	// ValidateCtx is like Validate but also receives the context passed to
+	// ValidateMap, so checks that do I/O, such as a DNS lookup, can honour
+	// cancellation. It runs after Validate. If the context is done once it
+	// returns, validation stops and reports the cancellation rather than the
+	// hook's result. Built-in Kind parsing never blocks and does not consult
+	// the context.
+	ValidateCtx func(ctx context.Context, raw string) error
+
+	// BoolTokens adds accepted KindBoolean tokens, mapped to the value they
+	// stand for, for example {"on": true, "off": false}. Tokens are matched
+	// case-insensitively and cannot override the built-in ones.
+	BoolTokens map[string]bool
+
+	// Base64URL decodes a KindBase64 value with the URL-safe alphabet instead
+	// of the standard one.
+	Base64URL bool
+
+	// StrictBase64 requires a KindBase64 value to be canonically padded. By
+	// default both padded and unpadded input are accepted.
+	StrictBase64 bool
+
+	// ByteLength, if non-zero, is the exact number of decoded bytes a KindHex
+	// or KindBase64 value must contain, for example 32 for a 256-bit key.
+	ByteLength int
+
+	// UUIDVersion, if non-zero, requires a KindUUID value to be an RFC 4122
+	// UUID of that version, for example 4 or 7.
+	UUIDVersion int
+
+	// CronSeconds lets a KindCron value have six fields, the first being
+	// seconds (0-59). Five-field expressions are still accepted.
+	CronSeconds bool
+
+	// AllowPartialVersion lets a KindSemver value omit the minor and patch
+	// components, which then default to zero.
+	AllowPartialVersion bool
+
+	// MustExist requires a KindFilePath value to name an existing, readable
+	// regular file (or directory when MustBeDir is set). This check touches
+	// the filesystem at validation time.
+	MustExist bool
+
+	// MustBeDir requires a KindFilePath value to name an existing, readable
+	// directory. It implies MustExist.
+	MustBeDir bool
+
+	// ExpandDefault expands $NAME and ${NAME} references in Default when the
+	// default is used. References are resolved from the input being
+	// validated: the map passed to ValidateMap, or the process environment
+	// for Validate. Without ExpandDefault a "$" in Default is literal.
+	ExpandDefault bool
+
+	// StrictExpand makes an ExpandDefault reference to a variable that is
+	// not set fail the field. By default such a reference is kept literally
+	// as ${NAME}.
+	StrictExpand bool
+
+	// Deprecated, if non-empty, marks the variable as deprecated. When it is
+	// set in the environment it is still validated normally, and a warning
+	// containing this message is reported through Validator.OnWarning and
+	// Result.Warnings.
+	Deprecated string
+
+	// Min, if set, is the inclusive lower bound for a KindInteger value, or
+	// for a KindBytes size in bytes.
+	Min *int64
+
+	// Max, if set, is the inclusive upper bound for a KindInteger value, or
+	// for a KindBytes size in bytes.
+	Max *int64
+
+	// MinFloat, if set, is the inclusive lower bound for a KindFloat value.
+	MinFloat *float64
+
+	// MaxFloat, if set, is the inclusive upper bound for a KindFloat value.
+	MaxFloat *float64
+
+	// MinDuration, if set, is the inclusive lower bound for a KindDuration
+	// value, for rejecting values such as "5ms" that are too short to work.
+	MinDuration *time.Duration
+
+	// MaxDuration, if set, is the inclusive upper bound for a KindDuration
+	// value.
+	MaxDuration *time.Duration
+
+	// MinLen, if positive, is the minimum length of a KindString value,
+	// counted in runes unless LenInBytes is set.
+	MinLen int
+
+	// MaxLen, if positive, is the maximum length of a KindString value,
+	// counted in runes unless LenInBytes is set.
+	MaxLen int
+
+	// LenInBytes makes MinLen and MaxLen count bytes instead of runes.
+	LenInBytes bool
+
+	// WeakValues lists values that are rejected regardless of case, such as
+	// "changeme" or "password", so a placeholder secret left in place fails
+	// at startup. It applies to defaults too and is checked before the value
+	// is parsed. The error never echoes the value, even for fields not marked
+	// Secret; combine it with MinLen to also require a minimum length.
+	WeakValues []string
 }
 
 // FieldSchema is the machine-readable description of a single field as
 // returned by Validator.Schema. It is safe to marshal to JSON.
+//
+// Key is the environment variable name actually looked up, including any
+// prefix or Alias. ResultKey is set only when the key used with Result
+// accessors differs from Key. For an indexed field, Key is the pattern such
+// as "ORIGIN_#", Indexed is set, and Kind is the kind of each element.
 type FieldSchema struct {
-	Key           string   `json:"key"`
-	Kind          string   `json:"kind"`
-	Required      bool     `json:"required"`
-	Default       string   `json:"default,omitempty"`
-	Description   string   `json:"description,omitempty"`
-	AllowedValues []string `json:"allowed_values,omitempty"`
+	Key                    string   `json:"key"`
+	ResultKey              string   `json:"result_key,omitempty"`
+	Kind                   string   `json:"kind"`
+	AnyOf                  []string `json:"any_of,omitempty"`
+	Required               bool     `json:"required"`
+	Default                string   `json:"default,omitempty"`
+	Description            string   `json:"description,omitempty"`
+	Group                  string   `json:"group,omitempty"`
+	AllowedValues          []string `json:"allowed_values,omitempty"`
+	CaseInsensitiveAllowed bool     `json:"case_insensitive_allowed,omitempty"`
+	Pattern                string   `json:"pattern,omitempty"`
+	Secret                 bool     `json:"secret,omitempty"`
+	Deprecated             string   `json:"deprecated,omitempty"`
+	Min                    *int64   `json:"min,omitempty"`
+	Max                    *int64   `json:"max,omitempty"`
+	MinFloat               *float64 `json:"min_float,omitempty"`
+	MaxFloat               *float64 `json:"max_float,omitempty"`
+	MinDuration            string   `json:"min_duration,omitempty"`
+	MaxDuration            string   `json:"max_duration,omitempty"`
+	MinLen                 int      `json:"min_len,omitempty"`
+	MaxLen                 int      `json:"max_len,omitempty"`
+	Indexed                bool     `json:"indexed,omitempty"`
 }
 
+// Sentinel errors wrapped by ValidationError values produced during
+// validation. Use errors.Is to test for a category of failure:
+//
+//	if errors.Is(err, envvalidator.ErrRequiredMissing) {
+//	    // at least one required variable is unset
+//	}
+var (
+	// ErrRequiredMissing indicates that a required variable was absent or empty.
+	ErrRequiredMissing = errors.New("required variable is missing")
+
+	// ErrNotAllowed indicates that a value is not one of the field's AllowedValues.
+	ErrNotAllowed = errors.New("value is not allowed")
+
+	// ErrInvalidValue indicates that a value could not be parsed as the field's
+	// Kind or violated one of its constraints.
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrConflict indicates that more than one member of an ExclusiveGroup or
+	// ExactlyOneOf group was set, or, with Validator.CaseInsensitiveKeys,
+	// that several variables differing only in case were set.
+	ErrConflict = errors.New("conflicting variables are set")
+)
+
 // ValidationError describes a single field that failed validation.
 type ValidationError struct {
-	// Key is the environment variable name that caused the error.
+	// Key is the environment variable name that caused the error, including
+	// any Validator prefix.
 	Key string
 
 	// Reason is a human-readable description of why validation failed.
 	Reason string
+
+	// Err is the sentinel error describing the category of failure, such as
+	// ErrRequiredMissing. It may be nil for a ValidationError built by hand.
+	Err error
+
+	// Kind is the field's declared Kind, for errors about a declared field.
+	Kind Kind
+
+	// Value is the offending value, after defaults and transforms, for errors
+	// about a value that was present. It is "<redacted>" for a Secret field
+	// and empty when the variable was missing.
+	Value string
 }
 
 // Error implements the error interface.
@@ -83,6 +497,32 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("env-validator: field %q: %s", e.Key, e.Reason)
 }
 
+// Unwrap returns the sentinel error wrapped by e, if any.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// LogValue implements slog.LogValuer, logging e as a group with "key",
+// "reason", and, when set, "kind" and "value" attributes. Secret values are
+// already masked in Value and Reason.
+//
+// Example:
+//
+//	var verr *envvalidator.ValidationError
+//	if errors.As(err, &verr) {
+//	    slog.Error("invalid configuration", "err", verr)
+//	}
+func (e *ValidationError) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("key", e.Key), slog.String("reason", e.Reason)}
+	if e.Kind != "" {
+		attrs = append(attrs, slog.String("kind", string(e.Kind)))
+	}
+	if e.Value != "" {
+		attrs = append(attrs, slog.String("value", e.Value))
+	}
+	return slog.GroupValue(attrs...)
+}
+
 // ValidationErrors is a slice of ValidationError values returned when one or
 // more fields fail validation. It implements the error interface so callers
 // can treat the entire batch as a single error.
@@ -100,83 +540,1052 @@ func (ve ValidationErrors) Error() string {
 	return out
 }
 
+// Unwrap returns the individual errors so that errors.Is and errors.As can
+// inspect each *ValidationError in the batch.
+func (ve ValidationErrors) Unwrap() []error {
+	out := make([]error, len(ve))
+	for i, e := range ve {
+		out[i] = e
+	}
+	return out
+}
+
+// LogValue implements slog.LogValuer, logging ve as a group with one entry
+// per error, named by its Key and holding the attributes described on
+// ValidationError.LogValue, so slog.Any("err", errs) stays queryable.
+//
+// Example:
+//
+//	if errs, ok := err.(envvalidator.ValidationErrors); ok {
+//	    slog.Error("invalid configuration", "errors", errs)
+//	}
+func (ve ValidationErrors) LogValue() slog.Value {
+	attrs := make([]slog.Attr, len(ve))
+	for i, e := range ve {
+		attrs[i] = slog.Any(e.Key, e)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// ByKey returns the first error recorded for the given key, or nil if that key
+// did not fail validation.
+//
+// Example:
+//
+//	if errs, ok := err.(envvalidator.ValidationErrors); ok {
+//	    if e := errs.ByKey("DATABASE_URL"); e != nil {
+//	        // fall back to a secondary config source
+//	    }
+//	}
+func (ve ValidationErrors) ByKey(key string) *ValidationError {
+	for _, e := range ve {
+		if e.Key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// Keys returns the distinct keys that failed validation, in the order their
+// errors were recorded (field declaration order for ValidateMap).
+func (ve ValidationErrors) Keys() []string {
+	seen := make(map[string]bool, len(ve))
+	keys := make([]string, 0, len(ve))
+	for _, e := range ve {
+		if !seen[e.Key] {
+			seen[e.Key] = true
+			keys = append(keys, e.Key)
+		}
+	}
+	return keys
+}
+
+// Sorted returns a copy of ve ordered alphabetically by Key. Errors sharing a
+// key keep their recorded order. ve itself is not modified, so the default
+// declaration order remains available.
+//
+// Example:
+//
+//	if errs, ok := err.(envvalidator.ValidationErrors); ok {
+//	    fmt.Println(errs.Sorted())
+//	}
+func (ve ValidationErrors) Sorted() ValidationErrors {
+	out := make(ValidationErrors, len(ve))
+	copy(out, ve)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// ErrorFormatter renders a batch of validation errors as text. PlainFormatter,
+// CompactFormatter, and JSONFormatter are provided; any function with this
+// signature can be passed to ValidationErrors.Format.
+type ErrorFormatter func(ValidationErrors) string
+
+// Format renders ve with f, leaving the Error output unchanged.
+//
+// Example:
+//
+//	if errs, ok := err.(envvalidator.ValidationErrors); ok {
+//	    log.Print(errs.Format(envvalidator.CompactFormatter))
+//	}
+func (ve ValidationErrors) Format(f ErrorFormatter) string {
+	return f(ve)
+}
+
+// PlainFormatter renders ve exactly as ValidationErrors.Error does: a count
+// followed by one indented line per error.
+func PlainFormatter(ve ValidationErrors) string {
+	return ve.Error()
+}
+
+// CompactFormatter renders ve on a single line, such as
+// "env-validator: 2 errors: PORT: cannot parse ...; DATABASE_URL: required
+// variable is missing or empty", for log lines that must not wrap.
+func CompactFormatter(ve ValidationErrors) string {
+	if len(ve) == 0 {
+		return ""
+	}
+	parts := make([]string, len(ve))
+	for i, e := range ve {
+		parts[i] = e.Key + ": " + e.Reason
+	}
+	noun := "errors"
+	if len(ve) == 1 {
+		noun = "error"
+	}
+	return fmt.Sprintf("env-validator: %d %s: %s", len(ve), noun, strings.Join(parts, "; "))
+}
+
+// JSONFormatter renders ve as the JSON document produced by
+// ValidationErrors.JSON.
+func JSONFormatter(ve ValidationErrors) string {
+	data, err := ve.JSON()
+	if err != nil {
+		return ve.Error()
+	}
+	return string(data)
+}
+
+// JSON encodes ve as a JSON array with one object per error, holding "key",
+// "reason", and, when set, "kind" and "value" members, for endpoints that
+// report configuration problems to dashboards. Secret values are masked as
+// in ValidationError.Value. An empty ve encodes as [].
+//
+// Example:
+//
+//	if errs, ok := err.(envvalidator.ValidationErrors); ok {
+//	    data, _ := errs.JSON()
+//	    w.Header().Set("Content-Type", "application/json")
+//	    w.WriteHeader(http.StatusServiceUnavailable)
+//	    w.Write(data)
+//	}
+func (ve ValidationErrors) JSON() ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(jsonErrors(ve)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// jsonError is the JSON encoding of a ValidationError.
+type jsonError struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+	Kind   Kind   `json:"kind,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// jsonErrors converts ve to its JSON encoding, using an empty array rather
+// than null when ve is empty.
+func jsonErrors(ve ValidationErrors) []jsonError {
+	out := make([]jsonError, len(ve))
+	for i, e := range ve {
+		out[i] = jsonError{Key: e.Key, Reason: e.Reason, Kind: e.Kind, Value: e.Value}
+	}
+	return out
+}
+
+// UnknownKeysError is returned by ValidateMapStrict when the input contains
+// variables that no field declares. It is distinct from ValidationErrors so
+// callers can choose to treat it as a warning.
+type UnknownKeysError struct {
+	// Keys lists the undeclared variable names in sorted order.
+	Keys []string
+}
+
+// Error implements the error interface.
+func (e *UnknownKeysError) Error() string {
+	return fmt.Sprintf("env-validator: undeclared environment variables: %s", strings.Join(e.Keys, ", "))
+}
+
 // Result holds the successfully parsed and validated values from the
 // environment. Values are accessed by their field key.
+//
+// The typed accessors such as String, Integer, and Duration panic if the key
+// was not declared or holds a different kind, which keeps main terse. Each
+// has a sibling with an E suffix, such as StringE, that returns the same
+// problem as an error instead, for library code that must not panic; the
+// generic Get does the same for any stored type.
+//
+// Example:
+//
+//	timeout, err := result.DurationE("TIMEOUT")
+//	if err != nil {
+//	    return err
+//	}
 type Result struct {
-	values map[string]any
+	values  map[string]any
+	kinds   map[string]Kind
+	secrets map[string]bool
+	sources map[string]string
+
+	// names maps each field key to its variable name, and order lists the
+	// keys in declaration order.
+	names map[string]string
+	order []string
+
+	// defaulted records the keys whose value came from Field.Default, and
+	// allowed the AllowedValues each value was checked against.
+	defaulted map[string]bool
+	allowed   map[string][]string
+
+	// undeclared holds the raw inputs captured by Validator.CaptureAll.
+	undeclared map[string]string
+	warnings   []string
 }
 
 // String returns the string value for the given key. It panics if the key was
 // not declared or if the field Kind is not KindString.
 func (r *Result) String(key string) string {
-	v, ok := r.values[key]
-	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q was not declared in the validator", key))
-	}
-	s, ok := v.(string)
-	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q is not a string field", key))
-	}
-	return s
+	return must(r.StringE(key))
+}
+
+// StringE is like String but returns an error instead of panicking.
+func (r *Result) StringE(key string) (string, error) {
+	return resultAs[string](r, key, "", "a string field")
 }
 
 // Integer returns the int64 value for the given key. It panics if the key was
 // not declared or if the field Kind is not KindInteger.
 func (r *Result) Integer(key string) int64 {
-	v, ok := r.values[key]
-	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q was not declared in the validator", key))
+	return must(r.IntegerE(key))
+}
+
+// IntegerE is like Integer but returns an error instead of panicking.
+func (r *Result) IntegerE(key string) (int64, error) {
+	return resultAs[int64](r, key, "", "an integer field")
+}
+
+// Int is like Integer but returns a plain int. It panics as Integer does, and
+// also if the value does not fit in an int on the current platform.
+//
+// Example:
+//
+//	pool := make(chan struct{}, result.Int("WORKERS"))
+func (r *Result) Int(key string) int {
+	return must(r.IntE(key))
+}
+
+// IntE is like Int but returns an error instead of panicking.
+func (r *Result) IntE(key string) (int, error) {
+	n, err := r.IntegerE(key)
+	if err != nil {
+		return 0, err
 	}
-	n, ok := v.(int64)
-	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q is not an integer field", key))
+	if int64(int(n)) != n {
+		return 0, fmt.Errorf("env-validator: key %q value %d overflows int", key, n)
 	}
-	return n
+	return int(n), nil
 }
 
 // Float returns the float64 value for the given key. It panics if the key was
 // not declared or if the field Kind is not KindFloat.
 func (r *Result) Float(key string) float64 {
+	return must(r.FloatE(key))
+}
+
+// FloatE is like Float but returns an error instead of panicking.
+func (r *Result) FloatE(key string) (float64, error) {
+	return resultAs[float64](r, key, "", "a float field")
+}
+
+// Boolean returns the bool value for the given key. It panics if the key was
+// not declared or if the field Kind is not KindBoolean.
+func (r *Result) Boolean(key string) bool {
+	return must(r.BooleanE(key))
+}
+
+// BooleanE is like Boolean but returns an error instead of panicking.
+func (r *Result) BooleanE(key string) (bool, error) {
+	return resultAs[bool](r, key, "", "a boolean field")
+}
+
+// Port returns the port number for the given key. It panics if the key was
+// not declared or if the field Kind is not KindPort.
+func (r *Result) Port(key string) int {
+	return must(r.PortE(key))
+}
+
+// PortE is like Port but returns an error instead of panicking.
+func (r *Result) PortE(key string) (int, error) {
+	return resultAs[int](r, key, "", "a port field")
+}
+
+// JSON returns the decoded JSON value for the given key: a map[string]any,
+// []any, string, float64, bool, or nil. It panics if the key was not declared
+// or if the field Kind is not KindJSON.
+func (r *Result) JSON(key string) any {
+	return must(r.JSONE(key))
+}
+
+// JSONE is like JSON but returns an error instead of panicking.
+func (r *Result) JSONE(key string) (any, error) {
 	v, ok := r.values[key]
 	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q was not declared in the validator", key))
+		return nil, fmt.Errorf("env-validator: key %q was not declared in the validator", key)
 	}
-	f, ok := v.(float64)
-	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q is not a float field", key))
+	if r.kinds[key] != KindJSON {
+		return nil, fmt.Errorf("env-validator: key %q is not a JSON field", key)
 	}
-	return f
+	return v, nil
 }
 
-// Boolean returns the bool value for the given key. It panics if the key was
-// not declared or if the field Kind is not KindBoolean.
-func (r *Result) Boolean(key string) bool {
+// CIDR returns the network range for the given key. It panics if the key was
+// not declared or if the field Kind is not KindCIDR.
+func (r *Result) CIDR(key string) *net.IPNet {
+	return must(r.CIDRE(key))
+}
+
+// CIDRE is like CIDR but returns an error instead of panicking.
+func (r *Result) CIDRE(key string) (*net.IPNet, error) {
+	return resultAs[*net.IPNet](r, key, "", "a CIDR field")
+}
+
+// IP returns the IP address for the given key. It panics if the key was not
+// declared or if the field Kind is not KindIP.
+func (r *Result) IP(key string) net.IP {
+	return must(r.IPE(key))
+}
+
+// IPE is like IP but returns an error instead of panicking.
+func (r *Result) IPE(key string) (net.IP, error) {
+	return resultAs[net.IP](r, key, "", "an IP field")
+}
+
+// Strings returns the string elements of a KindList field whose ElementKind
+// is string-valued (such as KindString or KindURL). It panics if the key was
+// not declared or the value is not a list of strings.
+func (r *Result) Strings(key string) []string {
+	return must(r.StringsE(key))
+}
+
+// StringsE is like Strings but returns an error instead of panicking.
+func (r *Result) StringsE(key string) ([]string, error) {
+	return resultAs[[]string](r, key, "", "a list of strings")
+}
+
+// MustEnum runs the handler registered for the value of key, for dispatching
+// on an enum without a switch that silently ignores new values. It returns an
+// error, without running any handler, if the key was not declared or is not
+// string-valued, if any of the field's AllowedValues has no handler, or if
+// the value itself has none. Checking every allowed value means that adding
+// one to the declaration fails at startup until it is handled, even before
+// any deployment sets it.
+//
+// Example:
+//
+//	err := result.MustEnum("LOG_LEVEL", map[string]func(){
+//	    "debug": func() { logger.SetLevel(slog.LevelDebug) },
+//	    "info":  func() { logger.SetLevel(slog.LevelInfo) },
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *Result) MustEnum(key string, handlers map[string]func()) error {
 	v, ok := r.values[key]
 	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q was not declared in the validator", key))
+		return fmt.Errorf("env-validator: key %q was not declared in the validator", key)
 	}
-	b, ok := v.(bool)
+	s, ok := v.(string)
 	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q is not a boolean field", key))
+		return fmt.Errorf("env-validator: key %q holds %T, not string", key, v)
+	}
+	var unhandled []string
+	for _, allowed := range r.allowed[key] {
+		if handlers[allowed] == nil {
+			unhandled = append(unhandled, allowed)
+		}
 	}
-	return b
+	if len(unhandled) > 0 {
+		return fmt.Errorf("env-validator: key %q has no handler for allowed values: %s", key, strings.Join(unhandled, ", "))
+	}
+	handler := handlers[s]
+	if handler == nil {
+		shown := strconv.Quote(s)
+		if r.secrets[key] {
+			shown = redactedValue
+		}
+		return fmt.Errorf("env-validator: key %q value %s has no handler", key, shown)
+	}
+	handler()
+	return nil
+}
+
+// Map returns the entries of a KindMap field whose ElementKind is
+// string-valued (such as KindString or KindURL). It panics if the key was not
+// declared or the value is not a map of strings.
+//
+// Example:
+//
+//	for name, value := range result.Map("LABELS") {
+//	    span.SetAttributes(attribute.String(name, value))
+//	}
+func (r *Result) Map(key string) map[string]string {
+	return must(r.MapE(key))
+}
+
+// MapE is like Map but returns an error instead of panicking.
+func (r *Result) MapE(key string) (map[string]string, error) {
+	return resultAs[map[string]string](r, key, "", "a map of strings")
+}
+
+// Integers returns the elements of a KindList field whose ElementKind is
+// KindInteger. It panics if the key was not declared or the value is not a
+// list of integers.
+func (r *Result) Integers(key string) []int64 {
+	return must(r.IntegersE(key))
+}
+
+// IntegersE is like Integers but returns an error instead of panicking.
+func (r *Result) IntegersE(key string) ([]int64, error) {
+	return resultAs[[]int64](r, key, "", "a list of integers")
+}
+
+// Bytes returns the decoded bytes for the given key. It panics if the key was
+// not declared or if the field Kind does not decode to bytes (KindBase64 or
+// KindHex).
+func (r *Result) Bytes(key string) []byte {
+	return must(r.BytesE(key))
+}
+
+// BytesE is like Bytes but returns an error instead of panicking.
+func (r *Result) BytesE(key string) ([]byte, error) {
+	return resultAs[[]byte](r, key, "", "a bytes field")
+}
+
+// Bytes64 returns the number of bytes for the given KindBytes key. It panics
+// if the key was not declared or if the field Kind is not KindBytes.
+//
+// Example:
+//
+//	http.MaxBytesReader(w, r.Body, result.Bytes64("MAX_UPLOAD_SIZE"))
+func (r *Result) Bytes64(key string) int64 {
+	return must(r.Bytes64E(key))
+}
+
+// Bytes64E is like Bytes64 but returns an error instead of panicking.
+func (r *Result) Bytes64E(key string) (int64, error) {
+	return resultAs[int64](r, key, KindBytes, "a byte size field")
+}
+
+// Semver returns the components of the semantic version for the given key.
+// It panics if the key was not declared or if the field Kind is not
+// KindSemver. Use SemverE or Raw to obtain the full SemanticVersion,
+// including build metadata.
+func (r *Result) Semver(key string) (major, minor, patch int, pre string) {
+	sv := must(r.SemverE(key))
+	return sv.Major, sv.Minor, sv.Patch, sv.Prerelease
+}
+
+// SemverE is like Semver but returns the full SemanticVersion, or an error
+// instead of panicking.
+func (r *Result) SemverE(key string) (SemanticVersion, error) {
+	return resultAs[SemanticVersion](r, key, "", "a semver field")
 }
 
 // Duration returns the time.Duration value for the given key. It panics if
 // the key was not declared or if the field Kind is not KindDuration.
-func (r *Result) Duration(key string) interface{} {
-	// Returned as interface{} to avoid a time import in this file.
-	// The concrete type is time.Duration. Use validator.go's Duration accessor.
+func (r *Result) Duration(key string) time.Duration {
+	return must(r.DurationE(key))
+}
+
+// DurationE is like Duration but returns an error instead of panicking.
+func (r *Result) DurationE(key string) (time.Duration, error) {
+	return resultAs[time.Duration](r, key, "", "a duration field")
+}
+
+// Enum returns the allowed value selected for the given KindEnum key. It
+// panics if the key was not declared or if the field Kind is not KindEnum.
+//
+// Example:
+//
+//	switch result.Enum("LOG_LEVEL") {
+//	case "debug":
+//	    logger.SetLevel(slog.LevelDebug)
+//	}
+func (r *Result) Enum(key string) string {
+	return must(r.EnumE(key))
+}
+
+// EnumE is like Enum but returns an error instead of panicking.
+func (r *Result) EnumE(key string) (string, error) {
+	return resultAs[string](r, key, KindEnum, "an enum field")
+}
+
+// Percent returns the value for the given KindPercentage key, on the 0-100
+// scale or as a fraction when Field.PercentAsFraction is set. It panics if
+// the key was not declared or if the field Kind is not KindPercentage.
+//
+// Example:
+//
+//	if cpuUsage > result.Percent("CPU_THRESHOLD") {
+//	    shedLoad()
+//	}
+func (r *Result) Percent(key string) float64 {
+	return must(r.PercentE(key))
+}
+
+// PercentE is like Percent but returns an error instead of panicking.
+func (r *Result) PercentE(key string) (float64, error) {
+	return resultAs[float64](r, key, KindPercentage, "a percentage field")
+}
+
+// Regexp returns the compiled expression for the given KindRegex key. It
+// panics if the key was not declared or if the field Kind is not KindRegex.
+//
+// Example:
+//
+//	if result.Regexp("PATH_FILTER").MatchString(r.URL.Path) {
+//	    return
+//	}
+func (r *Result) Regexp(key string) *regexp.Regexp {
+	return must(r.RegexpE(key))
+}
+
+// RegexpE is like Regexp but returns an error instead of panicking.
+func (r *Result) RegexpE(key string) (*regexp.Regexp, error) {
+	return resultAs[*regexp.Regexp](r, key, "", "a regex field")
+}
+
+// Cron returns the parsed schedule for the given KindCron key. It panics if
+// the key was not declared or if the field Kind is not KindCron.
+//
+// Example:
+//
+//	scheduler.Add(result.Cron("BACKUP_SCHEDULE").String(), runBackup)
+func (r *Result) Cron(key string) CronSchedule {
+	return must(r.CronE(key))
+}
+
+// CronE is like Cron but returns an error instead of panicking.
+func (r *Result) CronE(key string) (CronSchedule, error) {
+	return resultAs[CronSchedule](r, key, "", "a cron field")
+}
+
+// Location returns the *time.Location value for the given key. It panics if
+// the key was not declared or if the field Kind is not KindTimezone.
+//
+// Example:
+//
+//	now := time.Now().In(result.Location("SCHEDULER_TZ"))
+func (r *Result) Location(key string) *time.Location {
+	return must(r.LocationE(key))
+}
+
+// LocationE is like Location but returns an error instead of panicking.
+func (r *Result) LocationE(key string) (*time.Location, error) {
+	return resultAs[*time.Location](r, key, "", "a timezone field")
+}
+
+// resultAs returns the value for key as T, or an error if the key was not
+// declared or its value is not a T. When kind is set, the field must also
+// have been parsed as kind. what describes the expected field in the error.
+func resultAs[T any](r *Result, key string, kind Kind, what string) (T, error) {
+	var zero T
 	v, ok := r.values[key]
 	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q was not declared in the validator", key))
+		return zero, fmt.Errorf("env-validator: key %q was not declared in the validator", key)
+	}
+	t, ok := v.(T)
+	if !ok || (kind != "" && r.kinds[key] != kind) {
+		return zero, fmt.Errorf("env-validator: key %q is not %s", key, what)
+	}
+	return t, nil
+}
+
+// must returns v, or panics with the message of err, for the accessors that
+// panic rather than return an error.
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err.Error())
 	}
 	return v
 }
 
+// set records the parsed value for f along with its metadata, including the
+// variable name it is read from and the kind it was parsed as.
+func (r *Result) set(f Field, name string, kind Kind, parsed any, source string) {
+	key := resultKey(f)
+	if _, seen := r.values[key]; !seen {
+		r.order = append(r.order, key)
+	}
+	r.names[key] = name
+	r.values[key] = parsed
+	r.kinds[key] = kind
+	if f.Secret {
+		r.secrets[key] = true
+	}
+	if source != "" {
+		r.sources[key] = source
+	}
+}
+
+// Warnings returns the non-fatal warnings produced during validation, such as
+// deprecated variables being set, in the order they occurred.
+func (r *Result) Warnings() []string {
+	return append([]string(nil), r.warnings...)
+}
+
+// DefaultedKeys returns, in declaration order, the keys of the fields whose
+// value came from Field.DefaultFunc or Field.Default because their variable
+// was absent or empty. Fields with no variable and no non-empty default are
+// not listed.
+//
+// Example:
+//
+//	if keys := result.DefaultedKeys(); len(keys) > 0 {
+//	    log.Printf("using defaults for: %s", strings.Join(keys, ", "))
+//	}
+func (r *Result) DefaultedKeys() []string {
+	var keys []string
+	for _, key := range r.order {
+		if r.defaulted[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Has reports whether key holds a validated value. It returns false for keys
+// that were not declared and, in a ValidatePartial result, for fields that
+// failed validation, so it can guard the panicking accessors.
+//
+// Example:
+//
+//	if result.Has("SENTRY_DSN") {
+//	    initSentry(result.String("SENTRY_DSN"))
+//	}
+func (r *Result) Has(key string) bool {
+	_, ok := r.values[key]
+	return ok
+}
+
+// Source returns the name of the environment variable that supplied the value
+// for key: the field's own variable or one of its Fallbacks. It returns an
+// empty string when the value came from Default or the key was not declared.
+//
+// Example:
+//
+//	log.Printf("PORT read from %s", result.Source("PORT"))
+func (r *Result) Source(key string) string {
+	return r.sources[key]
+}
+
+// Kind returns the kind the value for key was parsed as: the field's Kind,
+// or, for a field with AnyOf, the kind that matched. It returns an empty Kind
+// if the key was not declared or failed validation.
+//
+// Example:
+//
+//	if result.Kind("CACHE_BACKEND") == envvalidator.KindURL {
+//	    // connect to the remote cache
+//	}
+func (r *Result) Kind(key string) Kind {
+	return r.kinds[key]
+}
+
+// All returns a copy of all parsed values keyed by field key, for code that
+// does not know the fields at compile time. Values have the Go types listed
+// for each Kind, such as int64 for KindInteger, time.Duration for
+// KindDuration, and []byte for KindBase64; Kind reports which one applies.
+// Secret values are included unmasked, so use Redacted for logging.
+// Variables captured by Validator.CaptureAll are not included.
+//
+// Example:
+//
+//	for key, val := range result.All() {
+//	    plugin.Configure(key, val)
+//	}
+func (r *Result) All() map[string]any {
+	out := make(map[string]any, len(r.values))
+	for k, v := range r.values {
+		out[k] = v
+	}
+	return out
+}
+
+// Merge returns a new Result holding the values of r followed by those of
+// other, for combining the Results of several Sub validators. When both hold
+// the same key, other's value wins. Warnings and captured undeclared
+// variables are combined too. Neither r nor other is modified.
+//
+// Example:
+//
+//	result := a.Merge(b)
+func (r *Result) Merge(other *Result) *Result {
+	out := &Result{
+		values:    make(map[string]any, len(r.values)+len(other.values)),
+		kinds:     make(map[string]Kind),
+		secrets:   make(map[string]bool),
+		sources:   make(map[string]string),
+		names:     make(map[string]string),
+		defaulted: make(map[string]bool),
+		allowed:   make(map[string][]string),
+	}
+	for _, src := range []*Result{r, other} {
+		for _, key := range src.order {
+			if _, seen := out.values[key]; !seen {
+				out.order = append(out.order, key)
+			}
+			out.values[key] = src.values[key]
+			out.kinds[key] = src.kinds[key]
+			out.names[key] = src.names[key]
+			out.defaulted[key] = src.defaulted[key]
+			delete(out.allowed, key)
+			if allowed, ok := src.allowed[key]; ok {
+				out.allowed[key] = allowed
+			}
+			delete(out.secrets, key)
+			if src.secrets[key] {
+				out.secrets[key] = true
+			}
+			delete(out.sources, key)
+			if source, ok := src.sources[key]; ok {
+				out.sources[key] = source
+			}
+		}
+		out.warnings = append(out.warnings, src.warnings...)
+		if src.undeclared != nil {
+			if out.undeclared == nil {
+				out.undeclared = make(map[string]string)
+			}
+			for k, val := range src.undeclared {
+				out.undeclared[k] = val
+			}
+		}
+	}
+	return out
+}
+
+// Redacted returns a copy of all parsed values keyed by field key, with the
+// value of every Secret field replaced by the string "<redacted>". It is
+// intended for debug output and logging.
+//
+// Example:
+//
+//	log.Printf("effective config: %v", result.Redacted())
+func (r *Result) Redacted() map[string]any {
+	out := make(map[string]any, len(r.values))
+	for k, v := range r.values {
+		if r.secrets[k] {
+			out[k] = redactedValue
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// MarshalJSON implements json.Marshaler, encoding the parsed values as a JSON
+// object keyed by field key. Secret fields are encoded as "<redacted>", and
+// values with a canonical text form (durations, CIDR blocks, semantic
+// versions) are encoded as that string, so "2m" is rendered as "2m0s".
+// KindHex bytes are encoded as lowercase hex, as Normalize writes them.
+//
+// Example:
+//
+//	data, _ := json.MarshalIndent(result, "", "  ")
+//	fmt.Println(string(data))
+func (r *Result) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any, len(r.values))
+	for k, v := range r.Redacted() {
+		if b, ok := v.([]byte); ok && r.kinds[k] == KindHex {
+			// As in Normalize; other decoded bytes keep JSON's base64.
+			out[k] = hex.EncodeToString(b)
+			continue
+		}
+		out[k] = jsonFriendly(v)
+	}
+	return json.Marshal(out)
+}
+
+// InfoLabels returns the canonical text form of every non-secret value,
+// for labelling a constant "config_info" metric. Label names are the field
+// keys lowercased, with characters other than ASCII letters, digits, and
+// underscores replaced by "_", so PORT becomes "port". Values are rendered as
+// by Normalize, so durations read "2m0s" and booleans "true" or "false".
+// Secret fields are left out entirely.
+//
+// Example:
+//
+//	info := prometheus.NewGauge(prometheus.GaugeOpts{
+//	    Name:        "config_info",
+//	    ConstLabels: result.InfoLabels(),
+//	})
+//	info.Set(1)
+func (r *Result) InfoLabels() map[string]string {
+	out := make(map[string]string, len(r.values))
+	for _, key := range r.order {
+		if r.secrets[key] {
+			continue
+		}
+		out[labelName(key)] = r.text(key)
+	}
+	return out
+}
+
+// labelName converts key into a metric label name.
+func labelName(key string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			return c + ('a' - 'A')
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_':
+			return c
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// WriteShellExports writes one POSIX shell "export NAME='value'" line per
+// validated field, in declaration order, so the effective configuration can
+// be reproduced with "source". NAME is the variable name including any
+// prefix or Alias, values are in their canonical text form (durations as
+// "2m0s", booleans as "true" or "false"), and single quotes are escaped.
+// Secret values are written as "<redacted>" unless includeSecrets is true.
+// An indexed field such as "ORIGIN_#" gets one line per element, named
+// ORIGIN_0, ORIGIN_1, and so on.
+//
+// Example:
+//
+//	f, _ := os.Create("effective.env.sh")
+//	defer f.Close()
+//	if err := result.WriteShellExports(f, false); err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *Result) WriteShellExports(w io.Writer, includeSecrets bool) error {
+	var b strings.Builder
+	for _, key := range r.order {
+		names, texts := r.variables(key)
+		for i, value := range texts {
+			if r.secrets[key] && !includeSecrets {
+				value = redactedValue
+			}
+			fmt.Fprintf(&b, "export %s=%s\n", names[i], shellQuote(value))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellQuote wraps s in single quotes for a POSIX shell, ending the quote
+// around each embedded single quote.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// jsonFriendly converts parsed values whose default JSON encoding is
+// unhelpful into their canonical string form.
+func jsonFriendly(v any) any {
+	switch val := v.(type) {
+	case time.Duration:
+		return val.String()
+	case []time.Duration:
+		out := make([]string, len(val))
+		for i, d := range val {
+			out[i] = d.String()
+		}
+		return out
+	case map[string]time.Duration:
+		out := make(map[string]string, len(val))
+		for k, d := range val {
+			out[k] = d.String()
+		}
+		return out
+	case *net.IPNet:
+		return val.String()
+	case SemanticVersion:
+		return val.String()
+	case *time.Location:
+		return val.String()
+	case *regexp.Regexp:
+		return val.String()
+	case CronSchedule:
+		return val.String()
+	default:
+		return v
+	}
+}
+
+// text returns the canonical text form of the value stored for key. KindJSON
+// values are re-encoded compactly and decoded bytes are re-encoded as
+// lowercase hex for KindHex or standard base64 otherwise; everything else is
+// rendered by formatValue.
+func (r *Result) text(key string) string {
+	v := r.values[key]
+	if b, ok := v.([]byte); ok {
+		if r.kinds[key] == KindHex {
+			return hex.EncodeToString(b)
+		}
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	if r.kinds[key] == KindJSON {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return formatValue(v)
+}
+
+// formatValue renders a parsed value in its canonical text form: durations as
+// "2m0s", booleans as "true" or "false", lists joined with commas, and maps as
+// key=value entries sorted by key and joined with semicolons.
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case int:
+		return strconv.Itoa(val)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case fmt.Stringer:
+		return val.String()
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = formatValue(rv.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + "=" + formatValue(rv.MapIndex(reflect.ValueOf(k)).Interface())
+		}
+		return strings.Join(parts, ";")
+	}
+	return fmt.Sprint(v)
+}
+
 // Raw returns the raw parsed value for the given key as an empty interface.
 // Useful when the caller wants to perform their own type assertion.
+//
+// With Validator.CaptureAll, an undeclared variable that was present in the
+// input is also returned, as its unvalidated string value.
 func (r *Result) Raw(key string) (any, bool) {
-	v, ok := r.values[key]
+	if v, ok := r.values[key]; ok {
+		return v, ok
+	}
+	v, ok := r.undeclared[key]
 	return v, ok
 }
+
+// Undeclared returns a copy of the undeclared variables captured by
+// Validator.CaptureAll, keyed by variable name. Values whose names suggest a
+// secret (containing KEY, TOKEN, PASSWORD, SECRET, or CREDENTIAL, in any
+// case) are replaced with "<redacted>". It returns an empty map when
+// CaptureAll was not set.
+//
+// Example:
+//
+//	for k, val := range result.Undeclared() {
+//	    log.Printf("unvalidated %s=%s", k, val)
+//	}
+func (r *Result) Undeclared() map[string]string {
+	out := make(map[string]string, len(r.undeclared))
+	for k, v := range r.undeclared {
+		if looksSecret(k) {
+			v = redactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// looksSecret reports whether a variable name suggests a sensitive value.
+func looksSecret(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, marker := range []string{"KEY", "TOKEN", "PASSWORD", "SECRET", "CREDENTIAL"} {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the parsed value for key as type T, or an error if the key was
+// not declared or its value has a different type. Like the E-suffixed Result
+// accessors such as StringE, it never panics, which suits library code that
+// must not crash its host. T must be the exact stored type listed for the
+// field's Kind, for example int64 for KindInteger, int for KindPort, and
+// time.Duration for KindDuration.
+//
+// Example:
+//
+//	timeout, err := envvalidator.Get[time.Duration](result, "TIMEOUT")
+//	if err != nil {
+//	    return err
+//	}
+func Get[T any](r *Result, key string) (T, error) {
+	var zero T
+	v, ok := r.values[key]
+	if !ok {
+		return zero, fmt.Errorf("env-validator: key %q was not declared in the validator", key)
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("env-validator: key %q holds %T, not %T", key, v, zero)
+	}
+	return t, nil
+}
+
+// AllowedFrom converts typed string constants into the []string expected by
+// Field.AllowedValues, so an enum declared in Go and its validator cannot
+// drift apart.
+//
+// Example:
+//
+//	type LogLevel string
+//
+//	const (
+//	    LevelDebug LogLevel = "debug"
+//	    LevelInfo  LogLevel = "info"
+//	)
+//
+//	envvalidator.Field{
+//	    Key:           "LOG_LEVEL",
+//	    Kind:          envvalidator.KindEnum,
+//	    AllowedValues: envvalidator.AllowedFrom(LevelDebug, LevelInfo),
+//	}
+func AllowedFrom[T ~string](values ...T) []string {
+	out := make([]string, len(values))
+	for i, val := range values {
+		out[i] = string(val)
+	}
+	return out
+}
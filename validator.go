@@ -2,24 +2,100 @@ package envvalidator
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math"
+	"net"
+	"net/mail"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // Validator holds a declared set of environment variable fields and provides
 // methods to validate and parse values from any string-keyed map or from the
 // real process environment.
 type Validator struct {
-	fields []Field
+	// OnWarning, if set, is called for every non-fatal warning produced
+	// during validation, such as a Deprecated variable being set. key is the
+	// variable name the warning refers to. The same messages are available
+	// afterwards from Result.Warnings.
+	OnWarning func(key, msg string)
+
+	// TrimSpace removes leading and trailing whitespace from every value,
+	// including defaults, before Field.Transform and all other checks. Kinds
+	// other than KindString already ignore surrounding whitespace when
+	// parsing; this option extends that to string values.
+	TrimSpace bool
+
+	// TreatBlankAsEmpty makes a variable whose value is only whitespace count
+	// as empty, and therefore absent, so Required, Default, and Fallbacks
+	// apply as if it were unset; with Field.AllowEmpty it is read as "".
+	// Unlike TrimSpace it leaves other values untouched, so a KindString
+	// value such as "  x" keeps its whitespace. Do not enable it if a
+	// whitespace-only string is meaningful for some field.
+	TreatBlankAsEmpty bool
+
+	// CaseInsensitiveKeys matches input variables to declared names
+	// regardless of case, so "database_url" in the input satisfies
+	// DATABASE_URL. It applies to the map being validated; Validate and
+	// ValidateLayered read only the exact declared names from the process
+	// environment unless CaptureAll is also set. If two input variables
+	// differ only in case and match the same declared name, validation fails
+	// with an error wrapping ErrConflict that names both, since it is unclear
+	// which should be used.
+	CaseInsensitiveKeys bool
+
+	// CaptureAll records every input variable that is not declared (neither a
+	// field's variable nor one of its Fallbacks) in the Result without
+	// validating it. Validate and ValidateLayered then read the whole process
+	// environment rather than only the declared variables. Captured values
+	// are available from Result.Raw as strings and listed, with likely
+	// secrets masked, by Result.Undeclared; they never appear in
+	// Result.Redacted or Result.MarshalJSON.
+	CaptureAll bool
+
+	// SupportFileSuffix enables the Docker and Kubernetes secrets convention:
+	// when a field's variable (for example DATABASE_URL) and its Fallbacks are
+	// all absent or empty, the variable with "_FILE" appended
+	// (DATABASE_URL_FILE) is consulted, and if set, the file it names is read
+	// and its contents, minus one trailing newline, are used as the value. A
+	// file that cannot be read fails the field with an error naming the path.
+	SupportFileSuffix bool
+
+	// OnMissing, if set, is called for every field whose variable is absent
+	// or empty, whether or not validation then succeeds. key is the variable
+	// name including any prefix, and usedDefault reports whether the field's
+	// Default was applied. It is not called for variables that were provided.
+	OnMissing func(key string, usedDefault bool)
+
+	// OnFieldValidated, if set, is called once for every field after it has
+	// been validated, whether or not it passed. key is the variable name
+	// including any prefix, dur is how long the field took including its
+	// custom hooks, and err is the field's *ValidationError or nil. It is
+	// not called for fields skipped because ctx was done.
+	OnFieldValidated func(key string, dur time.Duration, err error)
+
+	fields   []Field
+	prefix   string
+	patterns map[string]*regexp.Regexp
+	groups   []fieldGroup
 }
 
 // New creates a new Validator from the given field declarations.
-// Duplicate keys are not checked at construction time; the first declaration
-// for a given key wins during validation.
+// Duplicate keys are not checked at construction time; the last declaration
+// for a given key wins in the Result. Use NewStrict to reject duplicates.
 //
 // Example:
 //
@@ -28,11 +104,175 @@ type Validator struct {
 //	    envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true, Description: "Postgres connection URL"},
 //	)
 func New(fields ...Field) *Validator {
-	return &Validator{fields: fields}
+	v := &Validator{fields: fields}
+	v.compilePatterns()
+	return v
+}
+
+// NewWithPrefix is like New but looks up every field under the given prefix,
+// so that Field{Key: "PORT"} with prefix "BILLING_" reads BILLING_PORT. Result
+// accessors still use the bare key, while Schema output and validation errors
+// report the full prefixed variable name.
+//
+// Example:
+//
+//	v := envvalidator.NewWithPrefix("BILLING_",
+//	    envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+//	)
+//	result, err := v.Validate(context.Background())
+//	port := result.Port("PORT") // read from BILLING_PORT
+func NewWithPrefix(prefix string, fields ...Field) *Validator {
+	v := New(fields...)
+	v.prefix = prefix
+	return v
+}
+
+// NewStrict is like New but returns an error naming every key that is
+// declared more than once, every Pattern that fails to compile, every
+// KindEnum field without AllowedValues, and every variable name containing
+// "=" or a control character, which can never be set. Names that are
+// merely unconventional, such as lowercase ones, are reported by Lint
+// instead.
+//
+// Example:
+//
+//	v, err := envvalidator.NewStrict(
+//	    envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger},
+//	    envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort},
+//	)
+//	// err: env-validator: duplicate field keys: PORT
+func NewStrict(fields ...Field) (*Validator, error) {
+	seen := make(map[string]int, len(fields))
+	var dups []string
+	for _, f := range fields {
+		seen[f.Key]++
+		if seen[f.Key] == 2 {
+			dups = append(dups, f.Key)
+		}
+	}
+	var errs []error
+	if len(dups) > 0 {
+		errs = append(errs, fmt.Errorf("env-validator: duplicate field keys: %s", strings.Join(dups, ", ")))
+	}
+	v := New(fields...)
+	for _, f := range fields {
+		if f.Kind == KindEnum && len(f.AllowedValues) == 0 && f.AllowedValuesFunc == nil {
+			errs = append(errs, fmt.Errorf("env-validator: field %q: KindEnum requires AllowedValues", f.Key))
+		}
+		for _, name := range v.variableNames(f) {
+			if reason := unsettableName(name); reason != "" {
+				errs = append(errs, fmt.Errorf("env-validator: field %q: variable name %q %s", f.Key, name, reason))
+			}
+		}
+	}
+	errs = append(errs, v.compilePatterns()...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return v, nil
+}
+
+// Clone returns an independent copy of v: its fields (copied as by Fields),
+// groups, prefix, and options. Changes to the clone, including through
+// With, do not affect v, so a shared base Validator can be extended safely.
+//
+// Example:
+//
+//	worker := base.Clone()
+//	worker.CaptureAll = true
+func (v *Validator) Clone() *Validator {
+	c := *v
+	c.fields = v.Fields()
+	c.groups = make([]fieldGroup, len(v.groups))
+	for i, g := range v.groups {
+		g.keys = append([]string(nil), g.keys...)
+		c.groups[i] = g
+	}
+	c.compilePatterns()
+	return &c
+}
+
+// With returns a clone of v with fields appended to its declarations. v is
+// not modified.
+//
+// Example:
+//
+//	base := envvalidator.New(envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true})
+//	api := base.With(envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"})
+//	worker := base.With(envvalidator.Field{Key: "QUEUE_URL", Kind: envvalidator.KindURL, Required: true})
+func (v *Validator) With(fields ...Field) *Validator {
+	c := v.Clone()
+	c.fields = append(c.fields, fields...)
+	c.compilePatterns()
+	return c
+}
+
+// Sub returns a copy of v whose fields are read under prefix, nested inside
+// v's own prefix, so one sub-schema can be validated several times under
+// different prefixes. Unlike NewWithPrefix, the prefix also becomes part of
+// each field key, including Alias, DeprecatedKeys, and group members, so
+// Results from several Sub validators can be combined with Result.Merge
+// without collisions. Fallbacks are used as written.
+//
+// Example:
+//
+//	upstream := envvalidator.New(
+//	    envvalidator.Field{Key: "URL", Kind: envvalidator.KindURL, Required: true},
+//	    envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "5s"},
+//	)
+//	a, errA := upstream.Sub("UPSTREAM_A_").ValidateMap(ctx, env)
+//	b, errB := upstream.Sub("UPSTREAM_B_").ValidateMap(ctx, env)
+//	// handle errA and errB
+//	result := a.Merge(b)
+//	timeout := result.Duration("UPSTREAM_B_TIMEOUT")
+func (v *Validator) Sub(prefix string) *Validator {
+	c := v.Clone()
+	for i := range c.fields {
+		f := &c.fields[i]
+		f.Key = prefix + f.Key
+		if f.Alias != "" {
+			f.Alias = prefix + f.Alias
+		}
+		for j, old := range f.DeprecatedKeys {
+			f.DeprecatedKeys[j] = prefix + old
+		}
+	}
+	for i := range c.groups {
+		for j, key := range c.groups[i].keys {
+			c.groups[i].keys[j] = prefix + key
+		}
+	}
+	return c
+}
+
+// compilePatterns compiles every distinct Pattern declared on the fields and
+// caches the result. A pattern that fails to compile is left out of the cache
+// and reported at validation time; the compile errors are also returned so
+// that NewStrict can surface them.
+func (v *Validator) compilePatterns() []error {
+	var errs []error
+	v.patterns = make(map[string]*regexp.Regexp)
+	for _, f := range v.fields {
+		if f.Pattern == "" {
+			continue
+		}
+		if _, done := v.patterns[f.Pattern]; done {
+			continue
+		}
+		re, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("env-validator: field %q: invalid pattern %q: %w", f.Key, f.Pattern, err))
+			continue
+		}
+		v.patterns[f.Pattern] = re
+	}
+	return errs
 }
 
 // Validate reads environment variables from the real process environment using
-// os.Getenv, validates them against the declared fields, and returns a Result.
+// os.LookupEnv, validates them against the declared fields, and returns a Result.
+// A variable set to the empty string counts as absent unless its field sets
+// AllowEmpty.
 //
 // If any field fails validation, a ValidationErrors value is returned. The
 // caller should check for this type to inspect individual failures.
@@ -45,18 +285,181 @@ func New(fields ...Field) *Validator {
 //	}
 //	port := result.Integer("PORT")
 func (v *Validator) Validate(ctx context.Context) (*Result, error) {
-	env := make(map[string]string)
+	return v.ValidateMap(ctx, v.processEnv(make(map[string]string)))
+}
+
+// ValidateWith is like Validate but reads variables through getenv instead of
+// os.LookupEnv, so code that validates the environment indirectly can be
+// tested with a fake one. Only declared variables (and their Fallbacks,
+// DeprecatedKeys, and related names) are requested, so with CaptureAll no
+// undeclared variables are captured. Because getenv cannot report whether a
+// variable is set, an empty result counts as absent even with AllowEmpty,
+// and the variables of an indexed field are read up to the first empty one
+// rather than checked for gaps.
+//
+// Example:
+//
+//	fake := map[string]string{"DATABASE_URL": "postgres://localhost/test"}
+//	result, err := v.ValidateWith(context.Background(), func(key string) string {
+//	    return fake[key]
+//	})
+func (v *Validator) ValidateWith(ctx context.Context, getenv func(string) string) (*Result, error) {
+	lookupEnv := func(key string) (string, bool) {
+		val := getenv(key)
+		return val, val != ""
+	}
+	return v.ValidateMap(ctx, v.collectEnv(make(map[string]string), lookupEnv))
+}
+
+// ValidateLayered validates base with the process environment layered on
+// top: every declared variable (including Fallbacks) that is non-empty in
+// os.Getenv overrides the entry in base. This is the common "defaults from a
+// file, overrides from the environment" arrangement. base is not modified.
+//
+// Example:
+//
+//	base, err := envvalidator.LoadDotEnv(".env")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	result, err := v.ValidateLayered(context.Background(), base)
+func (v *Validator) ValidateLayered(ctx context.Context, base map[string]string) (*Result, error) {
+	env := make(map[string]string, len(base))
+	for k, val := range base {
+		env[k] = val
+	}
+	return v.ValidateMap(ctx, v.processEnv(env))
+}
+
+// processEnv copies every non-empty declared variable, and every variable
+// referenced by an ExpandDefault default, from os.Getenv into env,
+// overwriting existing entries, and returns env. The variables of indexed
+// fields are found by scanning os.Environ, so gaps can be reported. With
+// CaptureAll, every non-empty variable in os.Environ is copied instead.
+func (v *Validator) processEnv(env map[string]string) map[string]string {
+	if v.CaptureAll {
+		for _, kv := range os.Environ() {
+			if key, val, ok := strings.Cut(kv, "="); ok && val != "" {
+				env[key] = val
+			}
+		}
+		return env
+	}
+	env = v.collectEnv(env, os.LookupEnv)
+	for _, f := range v.fields {
+		if !indexed(f) {
+			continue
+		}
+		base := strings.TrimSuffix(v.envKey(f), "#")
+		for _, kv := range os.Environ() {
+			if key, val, ok := strings.Cut(kv, "="); ok && val != "" {
+				if _, ok := indexNumber(key, base); ok {
+					env[key] = val
+				}
+			}
+		}
+	}
+	return env
+}
+
+// collectEnv copies every non-empty declared variable, and every variable
+// referenced by an ExpandDefault default, from lookupEnv into env,
+// overwriting existing entries, and returns env. Variables of AllowEmpty
+// fields are copied when set, even if empty. For indexed fields, base0,
+// base1, ... are requested up to the first one that is empty.
+func (v *Validator) collectEnv(env map[string]string, lookupEnv func(string) (string, bool)) map[string]string {
 	for _, f := range v.fields {
-		if val := os.Getenv(f.Key); val != "" {
-			env[f.Key] = val
+		if indexed(f) {
+			base := strings.TrimSuffix(v.envKey(f), "#")
+			for i := 0; ; i++ {
+				name := base + strconv.Itoa(i)
+				val, _ := lookupEnv(name)
+				if val == "" {
+					break
+				}
+				env[name] = val
+			}
+		}
+		names := v.inputNames(f)
+		if f.ExpandDefault {
+			names = append(names, defaultRefs(f)...)
+		}
+		for _, name := range names {
+			if val, ok := lookupEnv(name); val != "" || (ok && f.AllowEmpty) {
+				env[name] = val
+			}
+		}
+	}
+	return env
+}
+
+// defaultRefs returns the names of the variables referenced by f.Default.
+func defaultRefs(f Field) []string {
+	var names []string
+	os.Expand(f.Default, func(name string) string {
+		names = append(names, name)
+		return ""
+	})
+	return names
+}
+
+// expandDefault expands the variable references in f.Default from env. A
+// reference to a variable that is absent from env is kept as ${NAME}, or
+// with StrictExpand, reported by name.
+func expandDefault(f Field, env map[string]string) (string, string) {
+	var unset []string
+	expanded := os.Expand(f.Default, func(name string) string {
+		if val, ok := env[name]; ok {
+			return val
+		}
+		unset = append(unset, name)
+		return "${" + name + "}"
+	})
+	if f.StrictExpand && len(unset) > 0 {
+		return "", fmt.Sprintf("default references unset variables: %s", strings.Join(unset, ", "))
+	}
+	return expanded, ""
+}
+
+// ValidateEnviron validates a snapshot of KEY=VALUE pairs, such as the output
+// of os.Environ or a subprocess's Env, without reading the process
+// environment. Each entry is split on its first "="; entries without one are
+// ignored and later duplicates override earlier ones.
+//
+// Example:
+//
+//	result, err := v.ValidateEnviron(context.Background(), cmd.Env)
+func (v *Validator) ValidateEnviron(ctx context.Context, environ []string) (*Result, error) {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if key, val, ok := strings.Cut(kv, "="); ok {
+			env[key] = val
 		}
 	}
 	return v.ValidateMap(ctx, env)
 }
 
+// MustValidate is like Validate but panics if validation fails. The panic
+// value is the returned error: a ValidationErrors for invalid fields, or the
+// context error if ctx is cancelled. It simplifies startup code in small
+// programs, mirroring regexp.MustCompile.
+//
+// Example:
+//
+//	result := v.MustValidate(context.Background())
+//	port := result.Integer("PORT")
+func (v *Validator) MustValidate(ctx context.Context) *Result {
+	result, err := v.Validate(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
 // ValidateMap validates the given key-value map against the declared fields
 // and returns a Result. This method is preferred for testing because it does
-// not read from os.Getenv.
+// not read from os.Getenv. Keys in env are full variable names, including
+// any prefix given to NewWithPrefix.
 //
 // Example:
 //
@@ -65,83 +468,733 @@ func (v *Validator) Validate(ctx context.Context) (*Result, error) {
 //	    "DATABASE_URL": "postgres://localhost/mydb",
 //	})
 func (v *Validator) ValidateMap(ctx context.Context, env map[string]string) (*Result, error) {
-	var errs ValidationErrors
-	values := make(map[string]any, len(v.fields))
+	result, errs, cancelled := v.validate(ctx, env, validateOptions{})
+	if cancelled != nil {
+		return nil, cancelled.Err
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// ValidateProfile validates env like ValidateMap with profile active, so
+// that fields listing profile in RequiredIn are mandatory in addition to
+// those marked Required. This keeps one declaration for several deployment
+// environments.
+//
+// Example:
+//
+//	v := envvalidator.New(
+//	    envvalidator.Field{Key: "SENTRY_DSN", Kind: envvalidator.KindURL, RequiredIn: []string{"prod"}},
+//	)
+//	result, err := v.ValidateProfile(context.Background(), env, os.Getenv("APP_ENV"))
+func (v *Validator) ValidateProfile(ctx context.Context, env map[string]string, profile string) (*Result, error) {
+	result, errs, cancelled := v.validate(ctx, env, validateOptions{profile: profile})
+	if cancelled != nil {
+		return nil, cancelled.Err
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// ValidateParallel validates env like ValidateMap but spreads the fields
+// across workers goroutines, which helps when Validate or ValidateCtx hooks
+// perform slow I/O such as network checks. Errors are still reported in
+// declaration order, and warnings, OnWarning, OnMissing, and OnFieldValidated
+// are delivered in that order from the calling goroutine once all fields are
+// done. Hooks (Transform, RequiredIf, Validate, ValidateCtx) run concurrently
+// and must be safe for concurrent use. Fields not yet started when ctx is
+// done are skipped and the context error is returned. A workers value below
+// 2 validates sequentially.
+//
+// Example:
+//
+//	result, err := v.ValidateParallel(ctx, env, 8)
+func (v *Validator) ValidateParallel(ctx context.Context, env map[string]string, workers int) (*Result, error) {
+	result, errs, cancelled := v.validate(ctx, env, validateOptions{workers: workers})
+	if cancelled != nil {
+		return nil, cancelled.Err
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return result, nil
+}
+
+// ValidateFast validates env like ValidateMap but stops at the first failing
+// field or group, returning that *ValidationError on its own instead of a
+// ValidationErrors. Fields after the failure are not validated, so their
+// hooks do not run and their warnings are not reported. It suits startup
+// paths where any failure means exiting.
+//
+// Example:
+//
+//	result, err := v.ValidateFast(context.Background(), env)
+//	if err != nil {
+//	    log.Fatal(err) // the first problem only
+//	}
+func (v *Validator) ValidateFast(ctx context.Context, env map[string]string) (*Result, error) {
+	result, errs, cancelled := v.validate(ctx, env, validateOptions{failFast: true})
+	if cancelled != nil {
+		return nil, cancelled.Err
+	}
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return result, nil
+}
+
+// ValidateMapStrict validates env like ValidateMap and additionally rejects
+// variables in env that no field declares, catching typos such as
+// DATABSE_URL. A variable counts as declared if it is any field's key or one
+// of its Fallbacks. When the Validator has a prefix, only variables starting
+// with that prefix are checked.
+//
+// Field validation errors take precedence and are returned as
+// ValidationErrors. If every field is valid but unknown variables are present,
+// the Result is returned together with an *UnknownKeysError, so callers can
+// downgrade it to a warning.
+//
+// Example:
+//
+//	result, err := v.ValidateMapStrict(ctx, env)
+//	var unknown *envvalidator.UnknownKeysError
+//	if errors.As(err, &unknown) {
+//	    log.Printf("warning: %v", unknown)
+//	} else if err != nil {
+//	    log.Fatal(err)
+//	}
+func (v *Validator) ValidateMapStrict(ctx context.Context, env map[string]string) (*Result, error) {
+	result, err := v.ValidateMap(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	declared := make(map[string]bool)
+	for _, f := range v.fields {
+		for _, name := range v.inputNames(f) {
+			declared[name] = true
+		}
+	}
+	var unknown []string
+	for key := range env {
+		if !declared[key] && !v.isIndexedVar(key) && strings.HasPrefix(key, v.prefix) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return result, &UnknownKeysError{Keys: unknown}
+	}
+	return result, nil
+}
+
+// ValidatePartial validates env like ValidateMap but always returns a Result
+// holding every field that passed, alongside the errors for the fields that
+// did not. It is intended for tooling that displays valid and invalid fields
+// together.
+//
+// If ctx is cancelled, validation stops and the context error is reported as
+// a ValidationError for the first field that was not validated, so
+// errors.Is(errs, context.Canceled) reports the cancellation.
+//
+// Example:
+//
+//	result, errs := v.ValidatePartial(context.Background(), env)
+//	fmt.Printf("%d of %d fields valid\n", len(v.Schema())-len(errs), len(v.Schema()))
+func (v *Validator) ValidatePartial(ctx context.Context, env map[string]string) (*Result, ValidationErrors) {
+	result, errs, cancelled := v.validate(ctx, env, validateOptions{})
+	if cancelled != nil {
+		errs = append(errs, cancelled)
+	}
+	return result, errs
+}
 
+// Normalize validates env like ValidateMap and returns the canonical text
+// form of every field, keyed by variable name (including any prefix or
+// Alias), for handing to another system such as a subprocess environment.
+// Defaults and transforms are applied, and values are re-rendered from their
+// parsed form, so "2m" becomes "2m0s" and "YES" becomes "true". Secret values
+// are included unmasked. Fields whose value is empty are omitted. An indexed
+// field such as "ORIGIN_#" is written as ORIGIN_0, ORIGIN_1, and so on, one
+// element each.
+//
+// Example:
+//
+//	normalized, err := v.Normalize(context.Background(), env)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	cmd := exec.Command("worker")
+//	for k, val := range normalized {
+//	    cmd.Env = append(cmd.Env, k+"="+val)
+//	}
+func (v *Validator) Normalize(ctx context.Context, env map[string]string) (map[string]string, error) {
+	result, err := v.ValidateMap(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(v.fields))
 	for _, f := range v.fields {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+		names, texts := result.variables(resultKey(f))
+		for i, text := range texts {
+			if text != "" {
+				out[names[i]] = text
+			}
+		}
+	}
+	return out, nil
+}
+
+// validateOptions adjusts how validate runs.
+type validateOptions struct {
+	// failFast stops at the first field or group error.
+	failFast bool
+	// profile is the active profile for Field.RequiredIn.
+	profile string
+	// workers, when greater than one, validates fields concurrently.
+	workers int
+}
+
+// validate runs every field against env and returns the values that parsed
+// together with the errors for those that did not, followed by any group
+// violations. If ctx is done before a field is reached, or once a field's
+// ValidateCtx hook has run, validation stops and the returned cancelled
+// error names that field and wraps ctx.Err().
+func (v *Validator) validate(ctx context.Context, env map[string]string, opts validateOptions) (result *Result, errs ValidationErrors, cancelled *ValidationError) {
+	result = &Result{
+		values:    make(map[string]any, len(v.fields)),
+		kinds:     make(map[string]Kind, len(v.fields)),
+		secrets:   make(map[string]bool),
+		sources:   make(map[string]string),
+		names:     make(map[string]string, len(v.fields)),
+		defaulted: make(map[string]bool),
+		allowed:   make(map[string][]string),
+	}
+
+	if v.CaseInsensitiveKeys {
+		env, errs = v.foldKeys(env)
+		if len(errs) > 0 {
+			if opts.failFast {
+				errs = errs[:1]
+			}
+			return result, errs, nil
 		}
+	}
 
-		kind := f.Kind
-		if kind == "" {
-			kind = KindString
+	var outcomes []fieldResult
+	if opts.workers > 1 {
+		outcomes = v.validateConcurrently(ctx, env, opts)
+	}
+	for i, f := range v.fields {
+		var fr fieldResult
+		if outcomes != nil {
+			fr = outcomes[i]
+		} else {
+			fr = v.runField(ctx, f, env, opts)
+		}
+		if fr.ctxErr != nil {
+			return result, errs, &ValidationError{Key: v.envKey(f), Reason: fr.ctxErr.Error(), Err: fr.ctxErr}
 		}
 
-		raw, present := env[f.Key]
-		if !present || raw == "" {
-			if f.Required && f.Default == "" {
-				errs = append(errs, &ValidationError{
-					Key:    f.Key,
-					Reason: "required variable is missing or empty",
-				})
-				continue
+		out, err := fr.out, fr.err
+		if v.OnFieldValidated != nil {
+			var hookErr error
+			if err != nil {
+				hookErr = err
+			}
+			v.OnFieldValidated(v.envKey(f), fr.dur, hookErr)
+		}
+		for _, w := range out.warnings {
+			result.warnings = append(result.warnings, w.msg)
+			if v.OnWarning != nil {
+				v.OnWarning(w.key, w.msg)
+			}
+		}
+		if out.missing && v.OnMissing != nil {
+			v.OnMissing(v.envKey(f), out.usedDefault)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			if opts.failFast {
+				return result, errs, nil
 			}
-			raw = f.Default
+			continue
+		}
+		result.set(f, v.envKey(f), out.kind, out.value, out.source)
+		result.defaulted[resultKey(f)] = out.usedDefault
+		if out.allowed != nil {
+			result.allowed[resultKey(f)] = out.allowed
 		}
+	}
+	groupErrs := v.checkGroups(env)
+	if opts.failFast && len(groupErrs) > 1 {
+		groupErrs = groupErrs[:1]
+	}
+	errs = append(errs, groupErrs...)
+	if v.CaptureAll {
+		result.undeclared = v.undeclared(env)
+	}
+	return result, errs, nil
+}
 
-		if len(f.AllowedValues) > 0 {
-			found := false
-			for _, allowed := range f.AllowedValues {
-				if raw == allowed {
-					found = true
-					break
+// fieldResult is the outcome of validating one field, or the context error
+// that stopped it.
+type fieldResult struct {
+	out    fieldOutcome
+	err    *ValidationError
+	ctxErr error
+	// dur is how long validateField took.
+	dur time.Duration
+}
+
+// runField validates f unless ctx is already done, and reports ctx.Err() if
+// the field's ValidateCtx hook ran and ctx was done afterwards.
+func (v *Validator) runField(ctx context.Context, f Field, env map[string]string, opts validateOptions) fieldResult {
+	if err := ctx.Err(); err != nil {
+		return fieldResult{ctxErr: err}
+	}
+	start := time.Now()
+	var out fieldOutcome
+	var err *ValidationError
+	if indexed(f) {
+		out, err = v.validateIndexed(ctx, f, env, opts)
+	} else {
+		out, err = v.validateField(ctx, f, env, opts)
+	}
+	dur := time.Since(start)
+	if out.ranCtxHook {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fieldResult{ctxErr: ctxErr}
+		}
+	}
+	return fieldResult{out: out, err: err, dur: dur}
+}
+
+// validateConcurrently runs every field on a pool of opts.workers
+// goroutines and returns the outcomes indexed like v.fields. Fields not yet
+// started when ctx is done report ctx.Err().
+func (v *Validator) validateConcurrently(ctx context.Context, env map[string]string, opts validateOptions) []fieldResult {
+	outcomes := make([]fieldResult, len(v.fields))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < opts.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				outcomes[i] = v.runField(ctx, v.fields[i], env, opts)
+			}
+		}()
+	}
+	for i := range v.fields {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return outcomes
+}
+
+// fieldOutcome is the result of validating a single field.
+type fieldOutcome struct {
+	value    any
+	kind     Kind
+	source   string
+	warnings []warning
+	// allowed holds the AllowedValues the value was checked against,
+	// including any loaded by AllowedValuesFunc.
+	allowed []string
+	// missing is set when no variable was found; usedDefault is set when
+	// Default was then applied.
+	missing, usedDefault bool
+	// ranCtxHook is set when a hook that receives ctx, ValidateCtx or
+	// AllowedValuesFunc, was called.
+	ranCtxHook bool
+}
+
+// warning is a non-fatal message about the variable key.
+type warning struct {
+	key, msg string
+}
+
+// validateField resolves the raw value for f from env, applies requiredness,
+// defaults, TrimSpace and Transform, AllowedValues, Kind parsing, Pattern,
+// and the custom Validate and ValidateCtx hooks, in that order. Warnings are
+// reported even when the field fails.
+func (v *Validator) validateField(ctx context.Context, f Field, env map[string]string, opts validateOptions) (fieldOutcome, *ValidationError) {
+	var out fieldOutcome
+	kind := f.Kind
+	if kind == "" {
+		kind = KindString
+	}
+
+	name := v.envKey(f)
+	raw, source := v.lookup(f, env)
+	// fail attaches the declared kind, and the current value if there is
+	// one, to e.
+	fail := func(e *ValidationError) (fieldOutcome, *ValidationError) {
+		e.Kind = kind
+		if e.Err != ErrRequiredMissing {
+			e.Value = raw
+			if f.Secret {
+				e.Value = redactedValue
+			}
+		}
+		return out, e
+	}
+	if source == "" && v.SupportFileSuffix {
+		fileVar := name + "_FILE"
+		if path := env[fileVar]; path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				reason := err
+				var pathErr *fs.PathError
+				if errors.As(err, &pathErr) {
+					reason = pathErr.Err
+				}
+				return out, &ValidationError{
+					Key:    name,
+					Reason: fmt.Sprintf("cannot read %s path %q: %v", fileVar, path, reason),
+					Err:    fmt.Errorf("%w: %w", ErrInvalidValue, err),
+					Kind:   kind,
 				}
 			}
-			if !found {
-				errs = append(errs, &ValidationError{
-					Key:    f.Key,
-					Reason: fmt.Sprintf("value %q is not one of the allowed values: %s", raw, strings.Join(f.AllowedValues, ", ")),
-				})
-				continue
+			content := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+			if content != "" {
+				raw, source = content, fileVar
+			}
+		}
+	}
+	if source != "" && v.isDeprecatedKey(f, source) {
+		out.warnings = append(out.warnings, warning{key: source, msg: fmt.Sprintf("%s is deprecated; use %s instead", source, name)})
+	}
+	if source != "" && f.Deprecated != "" {
+		out.warnings = append(out.warnings, warning{key: source, msg: fmt.Sprintf("%s is deprecated: %s", source, f.Deprecated)})
+	}
+	if source == "" {
+		out.missing = true
+		def := f.Default
+		fromFunc := false
+		if f.DefaultFunc != nil {
+			if computed := f.DefaultFunc(env); computed != "" {
+				def, fromFunc = computed, true
+			}
+		}
+		if f.Required && def == "" {
+			return fail(&ValidationError{
+				Key:    name,
+				Reason: "required variable is missing or empty",
+				Err:    ErrRequiredMissing,
+			})
+		}
+		if def == "" && requiredIn(f, opts.profile) {
+			return fail(&ValidationError{
+				Key:    name,
+				Reason: fmt.Sprintf("required in profile %q but is missing or empty", opts.profile),
+				Err:    ErrRequiredMissing,
+			})
+		}
+		if f.RequiredIf != nil && def == "" && f.RequiredIf(env) {
+			return fail(&ValidationError{
+				Key:    name,
+				Reason: conditionalReason(f),
+				Err:    ErrRequiredMissing,
+			})
+		}
+		raw = def
+		out.usedDefault = def != ""
+		if f.ExpandDefault && !fromFunc {
+			expanded, reason := expandDefault(f, env)
+			if reason != "" {
+				return fail(&ValidationError{Key: name, Reason: reason, Err: ErrInvalidValue})
 			}
+			raw = expanded
 		}
+	}
+
+	if v.TrimSpace {
+		raw = strings.TrimSpace(raw)
+	}
+	if f.Transform != nil {
+		raw = f.Transform(raw)
+	}
 
-		parsed, err := parseValue(f.Key, kind, raw)
+	if f.AllowedValuesFunc != nil {
+		out.ranCtxHook = true
+		allowed, err := f.AllowedValuesFunc(ctx)
 		if err != nil {
-			errs = append(errs, err)
+			return fail(&ValidationError{
+				Key:    name,
+				Reason: fmt.Sprintf("cannot load allowed values: %v", err),
+				Err:    fmt.Errorf("%w: %w", ErrInvalidValue, err),
+			})
+		}
+		f.AllowedValues = allowed
+	}
+	if len(f.AllowedValues) > 0 {
+		out.allowed = f.AllowedValues
+		found := false
+		for _, allowed := range f.AllowedValues {
+			if raw == allowed || (f.CaseInsensitiveAllowed && strings.EqualFold(raw, allowed)) {
+				raw = allowed
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fail(&ValidationError{
+				Key:    name,
+				Reason: fmt.Sprintf("value %s is not one of the allowed values: %s", displayValue(f, raw), strings.Join(f.AllowedValues, ", ")),
+				Err:    ErrNotAllowed,
+			})
+		}
+	}
+
+	for _, weak := range f.WeakValues {
+		if strings.EqualFold(raw, weak) {
+			// WeakValues usually guard secrets, so the value is masked even
+			// when the field is not marked Secret.
+			out, err := fail(&ValidationError{Key: name, Reason: "value is too weak (matches a known weak value)", Err: ErrInvalidValue})
+			err.Value = redactedValue
+			return out, err
+		}
+	}
+
+	parsed, matched, err := parseAnyOf(f, kind, raw)
+	if err != nil {
+		err.Key = name
+		err.Err = ErrInvalidValue
+		return fail(err)
+	}
+
+	if matched == KindString && f.Pattern != "" {
+		re, ok := v.patterns[f.Pattern]
+		if !ok {
+			return fail(&ValidationError{Key: name, Reason: fmt.Sprintf("pattern %q is not a valid regular expression", f.Pattern), Err: ErrInvalidValue})
+		}
+		if !re.MatchString(raw) {
+			return fail(&ValidationError{Key: name, Reason: fmt.Sprintf("value %s does not match required pattern %q", displayValue(f, raw), f.Pattern), Err: ErrInvalidValue})
+		}
+	}
+
+	if f.Validate != nil {
+		if err := f.Validate(raw); err != nil {
+			return fail(&ValidationError{Key: name, Reason: err.Error(), Err: fmt.Errorf("%w: %w", ErrInvalidValue, err)})
+		}
+	}
+	if f.ValidateCtx != nil {
+		out.ranCtxHook = true
+		if err := f.ValidateCtx(ctx, raw); err != nil {
+			return fail(&ValidationError{Key: name, Reason: err.Error(), Err: fmt.Errorf("%w: %w", ErrInvalidValue, err)})
+		}
+	}
+	out.value, out.kind, out.source = parsed, matched, source
+	return out, nil
+}
+
+// requiredIn reports whether profile is one of f's RequiredIn profiles.
+func requiredIn(f Field, profile string) bool {
+	if profile == "" {
+		return false
+	}
+	for _, p := range f.RequiredIn {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionalReason explains why a RequiredIf field was required.
+func conditionalReason(f Field) string {
+	if f.RequiredWhen != "" {
+		return fmt.Sprintf("required when %s but is missing or empty", f.RequiredWhen)
+	}
+	return "required by a condition but is missing or empty"
+}
+
+// envKey returns the environment variable name used to look up f: its Alias
+// or Key, with the Validator prefix applied.
+func (v *Validator) envKey(f Field) string {
+	if f.Alias != "" {
+		return v.prefix + f.Alias
+	}
+	return v.prefix + f.Key
+}
+
+// lookupNames returns every variable name that may supply a value for f, in
+// the order they are consulted.
+func (v *Validator) lookupNames(f Field) []string {
+	names := append([]string{v.envKey(f)}, f.Fallbacks...)
+	for _, old := range f.DeprecatedKeys {
+		names = append(names, v.prefix+old)
+	}
+	return names
+}
+
+// isDeprecatedKey reports whether name is one of f's DeprecatedKeys.
+func (v *Validator) isDeprecatedKey(f Field, name string) bool {
+	for _, old := range f.DeprecatedKeys {
+		if v.prefix+old == name {
+			return true
+		}
+	}
+	return false
+}
+
+// undeclared returns the entries of env that no field looks up.
+func (v *Validator) undeclared(env map[string]string) map[string]string {
+	declared := make(map[string]bool, len(v.fields))
+	for _, f := range v.fields {
+		for _, name := range v.inputNames(f) {
+			declared[name] = true
+		}
+	}
+	out := make(map[string]string)
+	for k, val := range env {
+		if !declared[k] && !v.isIndexedVar(k) {
+			out[k] = val
+		}
+	}
+	return out
+}
+
+// foldKeys returns a copy of env in which every variable whose name matches
+// a declared input name, ignoring case, is stored under the declared name.
+// Other variables are copied unchanged. Declared names matched by more than
+// one variable are reported as conflicts in declaration order.
+func (v *Validator) foldKeys(env map[string]string) (map[string]string, ValidationErrors) {
+	declared := make(map[string]string)
+	var order []string
+	for _, f := range v.fields {
+		names := v.inputNames(f)
+		if f.ExpandDefault {
+			names = append(names, defaultRefs(f)...)
+		}
+		for _, name := range names {
+			folded := strings.ToLower(name)
+			if _, ok := declared[folded]; !ok {
+				declared[folded] = name
+				order = append(order, name)
+			}
+		}
+	}
+	matches := make(map[string][]string)
+	out := make(map[string]string, len(env))
+	for k, val := range env {
+		name, ok := declared[strings.ToLower(k)]
+		if !ok {
+			out[k] = val
 			continue
 		}
-		values[f.Key] = parsed
+		matches[name] = append(matches[name], k)
+		out[name] = val
+	}
+	var errs ValidationErrors
+	for _, name := range order {
+		if keys := matches[name]; len(keys) > 1 {
+			sort.Strings(keys)
+			errs = append(errs, &ValidationError{
+				Key:    name,
+				Reason: fmt.Sprintf("%s all match %s when case is ignored; set only one", strings.Join(keys, ", "), name),
+				Err:    ErrConflict,
+			})
+		}
 	}
+	return out, errs
+}
 
-	if len(errs) > 0 {
-		return nil, errs
+// inputNames returns every variable name f can be read from: its lookup
+// names and, with SupportFileSuffix, its "_FILE" variable.
+func (v *Validator) inputNames(f Field) []string {
+	names := v.lookupNames(f)
+	if v.SupportFileSuffix {
+		names = append(names, v.envKey(f)+"_FILE")
+	}
+	return names
+}
+
+// lookup returns the first non-empty value for f in env, or with AllowEmpty
+// the first present one, along with the name of the variable that supplied
+// it. With TreatBlankAsEmpty, whitespace-only values count as empty. source
+// is empty when no candidate variable is set.
+func (v *Validator) lookup(f Field, env map[string]string) (raw, source string) {
+	for _, name := range v.lookupNames(f) {
+		val, ok := env[name]
+		if v.TreatBlankAsEmpty && strings.TrimSpace(val) == "" {
+			val = ""
+		}
+		if val != "" || (ok && f.AllowEmpty) {
+			return val, name
+		}
+	}
+	return "", ""
+}
+
+// parseAnyOf parses raw as kind, or, when f.AnyOf is set, as the first of
+// those kinds that accepts it. It returns the kind that was used.
+func parseAnyOf(f Field, kind Kind, raw string) (any, Kind, *ValidationError) {
+	if len(f.AnyOf) == 0 {
+		parsed, err := parseValue(f, kind, raw)
+		return parsed, kind, err
+	}
+	names := make([]string, len(f.AnyOf))
+	for i, k := range f.AnyOf {
+		if parsed, err := parseValue(f, k, raw); err == nil {
+			return parsed, k, nil
+		}
+		names[i] = string(k)
 	}
-	return &Result{values: values}, nil
+	return nil, "", &ValidationError{Key: f.Key, Reason: fmt.Sprintf("cannot parse %s as any of: %s", displayValue(f, raw), strings.Join(names, ", "))}
 }
 
-// parseValue converts a raw string into the Go type corresponding to kind.
-func parseValue(key string, kind Kind, raw string) (any, *ValidationError) {
+// parseValue converts a raw string into the Go type corresponding to kind and
+// applies any constraints declared on the field.
+func parseValue(f Field, kind Kind, raw string) (any, *ValidationError) {
+	key := f.Key
+	shown := displayValue(f, raw)
 	switch kind {
 	case KindString:
+		if reason := checkLength(f, raw); reason != "" {
+			return nil, &ValidationError{Key: key, Reason: reason}
+		}
 		return raw, nil
 
+	case KindEnum:
+		if len(f.AllowedValues) == 0 {
+			return nil, &ValidationError{Key: key, Reason: "enum field declares no AllowedValues"}
+		}
+		for _, allowed := range f.AllowedValues {
+			if raw == allowed || (f.CaseInsensitiveAllowed && strings.EqualFold(raw, allowed)) {
+				return allowed, nil
+			}
+		}
+		return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("value %s is not one of the allowed values: %s", shown, strings.Join(f.AllowedValues, ", "))}
+
 	case KindInteger:
 		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
 		if err != nil {
-			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %q as an integer", raw)}
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as an integer", shown)}
+		}
+		if reason := checkIntRange(f, n, f.Min, f.Max); reason != "" {
+			return nil, &ValidationError{Key: key, Reason: reason}
 		}
 		return n, nil
 
 	case KindFloat:
-		f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		n, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
 		if err != nil {
-			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %q as a float", raw)}
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a float", shown)}
+		}
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("%s is not a finite number", shown)}
 		}
-		return f, nil
+		if reason := checkFloatRange(f, n, f.MinFloat, f.MaxFloat); reason != "" {
+			return nil, &ValidationError{Key: key, Reason: reason}
+		}
+		return n, nil
 
 	case KindBoolean:
 		normalized := strings.ToLower(strings.TrimSpace(raw))
@@ -150,22 +1203,206 @@ func parseValue(key string, kind Kind, raw string) (any, *ValidationError) {
 			return true, nil
 		case "false", "0", "no":
 			return false, nil
-		default:
-			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %q as a boolean; accepted values are true, false, 1, 0, yes, no", raw)}
 		}
+		for token, b := range f.BoolTokens {
+			if strings.EqualFold(normalized, strings.TrimSpace(token)) {
+				return b, nil
+			}
+		}
+		return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a boolean; accepted values are %s", shown, boolTokenList(f.BoolTokens))}
 
 	case KindURL:
 		trimmed := strings.TrimSpace(raw)
 		u, err := url.ParseRequestURI(trimmed)
 		if err != nil || u.Scheme == "" || u.Host == "" {
-			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %q as an absolute URL with scheme and host", raw)}
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as an absolute URL with scheme and host", shown)}
 		}
 		return trimmed, nil
 
+	case KindRegex:
+		re, err := regexp.Compile(raw)
+		switch {
+		case err != nil && f.Secret:
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot compile %s as a regular expression", shown)}
+		case err != nil:
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot compile %s as a regular expression: %s", shown, err)}
+		}
+		return re, nil
+
+	case KindPercentage:
+		n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(raw), "%")), 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a percentage", shown)}
+		}
+		if n < 0 || n > 100 {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("percentage %s is outside the range 0 to 100", shown)}
+		}
+		if f.PercentAsFraction {
+			n /= 100
+		}
+		return n, nil
+
+	case KindPort:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a port; must be between 1 and 65535 (0 is not accepted)", shown)}
+		}
+		return int(n), nil
+
+	case KindEmail:
+		addr, err := mail.ParseAddress(strings.TrimSpace(raw))
+		if err != nil || addr.Name != "" {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as an email address", shown)}
+		}
+		return addr.Address, nil
+
+	case KindJSON:
+		var decoded any
+		if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+			var syntaxErr *json.SyntaxError
+			switch {
+			case f.Secret && errors.As(err, &syntaxErr):
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as JSON: syntax error at offset %d", shown, syntaxErr.Offset)}
+			case f.Secret:
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as JSON", shown)}
+			case errors.As(err, &syntaxErr):
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as JSON: %s at offset %d", shown, syntaxErr, syntaxErr.Offset)}
+			default:
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as JSON: %s", shown, err)}
+			}
+		}
+		return decoded, nil
+
+	case KindCIDR:
+		trimmed := strings.TrimSpace(raw)
+		_, network, err := net.ParseCIDR(trimmed)
+		if err != nil {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a CIDR block: %s", shown, cidrProblem(trimmed))}
+		}
+		return network, nil
+
+	case KindIP:
+		trimmed := strings.TrimSpace(raw)
+		ip := net.ParseIP(trimmed)
+		if ip == nil {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as an IP address", shown)}
+		}
+		isV6 := strings.Contains(trimmed, ":")
+		switch f.IPVersion {
+		case 0:
+		case 4:
+			if isV6 {
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("value %s is not an IPv4 address; expected IPv4", shown)}
+			}
+		case 6:
+			if !isV6 {
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("value %s is not an IPv6 address; expected IPv6", shown)}
+			}
+		default:
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("unsupported IPVersion %d; use 0, 4, or 6", f.IPVersion)}
+		}
+		return ip, nil
+
+	case KindList:
+		return parseList(f, raw)
+
+	case KindMap:
+		return parseMap(f, raw)
+
+	case KindBase64:
+		b, err := decodeBase64(f, strings.TrimSpace(raw))
+		if err != nil {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as base64", shown)}
+		}
+		if reason := checkByteLength(b, f.ByteLength); reason != "" {
+			return nil, &ValidationError{Key: key, Reason: reason}
+		}
+		return b, nil
+
+	case KindBytes:
+		n, problem := parseByteSize(strings.TrimSpace(raw))
+		if problem != "" {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a byte size: %s", shown, problem)}
+		}
+		if reason := checkIntRange(f, n, f.Min, f.Max); reason != "" {
+			return nil, &ValidationError{Key: key, Reason: reason}
+		}
+		return n, nil
+
+	case KindTimezone:
+		name := strings.TrimSpace(raw)
+		if name == "" {
+			return nil, &ValidationError{Key: key, Reason: "timezone is empty"}
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot load %s as an IANA timezone", shown)}
+		}
+		return loc, nil
+
+	case KindHex:
+		b, err := hex.DecodeString(strings.TrimSpace(raw))
+		if errors.Is(err, hex.ErrLength) {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as hex: odd number of digits", shown)}
+		}
+		if err != nil {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as hex", shown)}
+		}
+		if reason := checkByteLength(b, f.ByteLength); reason != "" {
+			return nil, &ValidationError{Key: key, Reason: reason}
+		}
+		return b, nil
+
+	case KindUUID:
+		u := strings.ToLower(strings.TrimSpace(raw))
+		if !isUUID(u) {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a UUID", shown)}
+		}
+		if f.UUIDVersion != 0 {
+			version := strings.IndexByte(hexDigits, u[14])
+			if version != f.UUIDVersion || !strings.ContainsRune("89ab", rune(u[19])) {
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("value %s is not an RFC 4122 version %d UUID", shown, f.UUIDVersion)}
+			}
+		}
+		return u, nil
+
+	case KindSemver:
+		sv, problem := parseSemver(strings.TrimSpace(raw), f.AllowPartialVersion)
+		if problem != "" {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a semantic version: %s", shown, problem)}
+		}
+		return sv, nil
+
+	case KindCron:
+		cs, problem := parseCron(raw, f.CronSeconds)
+		if problem != "" {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a cron expression: %s", shown, problem)}
+		}
+		return cs, nil
+
+	case KindFilePath:
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			return nil, &ValidationError{Key: key, Reason: "path is empty"}
+		}
+		path, err := filepath.Abs(trimmed)
+		if err != nil {
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot resolve %s as an absolute path", shown)}
+		}
+		if f.MustExist || f.MustBeDir {
+			if problem := checkPath(path, f.MustBeDir); problem != "" {
+				return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("path %s %s", shown, problem)}
+			}
+		}
+		return path, nil
+
 	case KindDuration:
 		d, err := time.ParseDuration(strings.TrimSpace(raw))
 		if err != nil {
-			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %q as a duration; use Go duration syntax such as 5s, 1m30s, or 2h", raw)}
+			return nil, &ValidationError{Key: key, Reason: fmt.Sprintf("cannot parse %s as a duration; use Go duration syntax such as 5s, 1m30s, or 2h", shown)}
+		}
+		if reason := checkDurationRange(f, d, f.MinDuration, f.MaxDuration); reason != "" {
+			return nil, &ValidationError{Key: key, Reason: reason}
 		}
 		return d, nil
 
@@ -174,20 +1411,398 @@ func parseValue(key string, kind Kind, raw string) (any, *ValidationError) {
 	}
 }
 
+// parseList splits raw on the field's Delimiter and parses every element as
+// the field's ElementKind, stopping at the first invalid element.
+func parseList(f Field, raw string) (any, *ValidationError) {
+	elemKind := f.ElementKind
+	if elemKind == "" {
+		elemKind = KindString
+	}
+	if elemKind == KindList || elemKind == KindMap {
+		return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("ElementKind cannot be %s", elemKind)}
+	}
+	delim := f.Delimiter
+	if delim == "" {
+		delim = ","
+	}
+
+	var items []any
+	if raw != "" {
+		for i, elem := range strings.Split(raw, delim) {
+			if f.TrimElements {
+				elem = strings.TrimSpace(elem)
+			}
+			if strings.TrimSpace(elem) == "" {
+				if f.DropEmpty {
+					continue
+				}
+				return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("element %d is empty", i)}
+			}
+			parsed, err := parseValue(f, elemKind, elem)
+			if err != nil {
+				return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("element %d: %s", i, err.Reason)}
+			}
+			items = append(items, parsed)
+		}
+	}
+	return typedSlice(elemKind, items), nil
+}
+
+// parseMap splits raw into key/value entries and parses each value as the
+// field's ElementKind.
+func parseMap(f Field, raw string) (any, *ValidationError) {
+	elemKind := f.ElementKind
+	if elemKind == "" {
+		elemKind = KindString
+	}
+	if elemKind == KindList || elemKind == KindMap {
+		return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("ElementKind cannot be %s", elemKind)}
+	}
+	delim := f.Delimiter
+	if delim == "" {
+		delim = ";"
+	}
+	sep := f.KeyValueSeparator
+	if sep == "" {
+		sep = "="
+	}
+
+	entries := make(map[string]any)
+	if raw != "" {
+		for i, entry := range strings.Split(raw, delim) {
+			if strings.TrimSpace(entry) == "" {
+				if f.DropEmpty {
+					continue
+				}
+				return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("entry %d is empty", i)}
+			}
+			k, val, ok := strings.Cut(entry, sep)
+			k = strings.TrimSpace(k)
+			switch {
+			case !ok:
+				return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("entry %d %s is missing the %q separator", i, displayValue(f, entry), sep)}
+			case k == "":
+				return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("entry %d has an empty key", i)}
+			}
+			if _, dup := entries[k]; dup {
+				return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("entry %d repeats key %q", i, k)}
+			}
+			parsed, err := parseValue(f, elemKind, strings.TrimSpace(val))
+			if err != nil {
+				return nil, &ValidationError{Key: f.Key, Reason: fmt.Sprintf("entry %q: %s", k, err.Reason)}
+			}
+			entries[k] = parsed
+		}
+	}
+	return typedMap(elemKind, entries), nil
+}
+
+// typedMap converts parsed map values into the map type matching the
+// element kind, in the same way as typedSlice.
+func typedMap(kind Kind, entries map[string]any) any {
+	switch kind {
+	case KindInteger:
+		return convertMap[int64](entries)
+	case KindFloat:
+		return convertMap[float64](entries)
+	case KindBoolean:
+		return convertMap[bool](entries)
+	case KindDuration:
+		return convertMap[time.Duration](entries)
+	case KindPort:
+		return convertMap[int](entries)
+	case KindString, KindURL, KindEmail, KindEnum:
+		return convertMap[string](entries)
+	default:
+		return entries
+	}
+}
+
+func convertMap[T any](entries map[string]any) map[string]T {
+	out := make(map[string]T, len(entries))
+	for k, v := range entries {
+		out[k] = v.(T)
+	}
+	return out
+}
+
+// typedSlice converts parsed list elements into the slice type matching the
+// element kind, so that callers can use Strings, Integers, or a plain type
+// assertion on Raw.
+func typedSlice(kind Kind, items []any) any {
+	switch kind {
+	case KindInteger:
+		return convertSlice[int64](items)
+	case KindFloat:
+		return convertSlice[float64](items)
+	case KindBoolean:
+		return convertSlice[bool](items)
+	case KindDuration:
+		return convertSlice[time.Duration](items)
+	case KindPort:
+		return convertSlice[int](items)
+	case KindString, KindURL, KindEmail, KindEnum:
+		return convertSlice[string](items)
+	default:
+		if items == nil {
+			return []any{}
+		}
+		return items
+	}
+}
+
+func convertSlice[T any](items []any) []T {
+	out := make([]T, len(items))
+	for i, item := range items {
+		out[i] = item.(T)
+	}
+	return out
+}
+
+// decodeBase64 decodes s using the alphabet and padding rules declared on f.
+func decodeBase64(f Field, s string) ([]byte, error) {
+	padded, unpadded := base64.StdEncoding, base64.RawStdEncoding
+	if f.Base64URL {
+		padded, unpadded = base64.URLEncoding, base64.RawURLEncoding
+	}
+	if f.StrictBase64 {
+		return padded.Strict().DecodeString(s)
+	}
+	if strings.HasSuffix(s, "=") || len(s)%4 == 0 {
+		return padded.DecodeString(s)
+	}
+	return unpadded.DecodeString(s)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// isUUID reports whether s, already lowercased, is in the canonical
+// 8-4-4-4-12 hex form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if strings.IndexByte(hexDigits, s[i]) < 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkPath verifies that path exists, is a directory or regular file as
+// requested, and can be opened for reading. It returns a description of the
+// first problem found, or an empty string.
+func checkPath(path string, wantDir bool) string {
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return "does not exist"
+	case err != nil:
+		return "cannot be accessed"
+	case wantDir && !info.IsDir():
+		return "is a file; expected a directory"
+	case !wantDir && info.IsDir():
+		return "is a directory; expected a file"
+	}
+	fh, err := os.Open(path)
+	if err != nil {
+		return "is not readable"
+	}
+	fh.Close()
+	return ""
+}
+
+// cidrProblem explains why s failed net.ParseCIDR, distinguishing a bad
+// address from a bad prefix length. It never echoes any part of s.
+func cidrProblem(s string) string {
+	addr, _, ok := strings.Cut(s, "/")
+	switch {
+	case !ok:
+		return "missing /prefix length"
+	case net.ParseIP(addr) == nil:
+		return "invalid IP address"
+	default:
+		return "invalid prefix length"
+	}
+}
+
+// redactedValue replaces the value of a Secret field wherever it would
+// otherwise be displayed.
+const redactedValue = "<redacted>"
+
+// displayValue returns raw quoted for use in a message, or redactedValue when
+// the field is Secret.
+func displayValue(f Field, raw string) string {
+	if f.Secret {
+		return redactedValue
+	}
+	return strconv.Quote(raw)
+}
+
+// boundedValue returns formatted, the parsed value as a range error shows it,
+// or the redacted placeholder for a Secret field. The range helpers all go
+// through it so that bounds violations never echo a secret.
+func boundedValue(f Field, formatted string) string {
+	if f.Secret {
+		return redactedValue
+	}
+	return formatted
+}
+
+// checkIntRange returns a non-empty reason when n falls outside the inclusive
+// bounds. Either bound may be nil, in which case that side is unbounded. The
+// value is masked for Secret fields.
+func checkIntRange(f Field, n int64, min, max *int64) string {
+	shown := boundedValue(f, strconv.FormatInt(n, 10))
+	switch {
+	case min != nil && max != nil && (n < *min || n > *max):
+		return fmt.Sprintf("value %s is out of range [%d, %d]", shown, *min, *max)
+	case min != nil && n < *min:
+		return fmt.Sprintf("value %s is below minimum %d", shown, *min)
+	case max != nil && n > *max:
+		return fmt.Sprintf("value %s is above maximum %d", shown, *max)
+	}
+	return ""
+}
+
+// boolTokenList describes the accepted boolean tokens: the built-in ones
+// followed by the extra truthy and then falsy tokens, each sorted.
+func boolTokenList(extra map[string]bool) string {
+	var truthy, falsy []string
+	for token, b := range extra {
+		if b {
+			truthy = append(truthy, token)
+		} else {
+			falsy = append(falsy, token)
+		}
+	}
+	sort.Strings(truthy)
+	sort.Strings(falsy)
+	tokens := append([]string{"true", "false", "1", "0", "yes", "no"}, truthy...)
+	return strings.Join(append(tokens, falsy...), ", ")
+}
+
+// byteUnit returns the multiplier for a lowercase size suffix.
+func byteUnit(unit string) (int64, bool) {
+	switch unit {
+	case "", "b":
+		return 1, true
+	case "kb":
+		return 1e3, true
+	case "mb":
+		return 1e6, true
+	case "gb":
+		return 1e9, true
+	case "tb":
+		return 1e12, true
+	case "kib":
+		return 1 << 10, true
+	case "mib":
+		return 1 << 20, true
+	case "gib":
+		return 1 << 30, true
+	case "tib":
+		return 1 << 40, true
+	}
+	return 0, false
+}
+
+// parseByteSize parses an integer followed by an optional unit, returning a
+// value-free problem description on failure.
+func parseByteSize(s string) (int64, string) {
+	i := 0
+	for i < len(s) && (s[i] == '-' || s[i] == '+' || (s[i] >= '0' && s[i] <= '9')) {
+		i++
+	}
+	digits, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	if digits == "" {
+		return 0, "missing number"
+	}
+	if strings.HasPrefix(unit, ".") {
+		return 0, "fractional sizes are not supported; use a smaller unit"
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, "number is not a valid integer"
+	}
+	if n < 0 {
+		return 0, "size must not be negative"
+	}
+	mult, ok := byteUnit(unit)
+	if !ok {
+		return 0, "unknown unit; use B, KB, MB, GB, TB, KiB, MiB, GiB, or TiB"
+	}
+	if n > math.MaxInt64/mult {
+		return 0, "size overflows int64"
+	}
+	return n * mult, ""
+}
+
+// checkLength returns a non-empty reason when raw violates f.MinLen or
+// f.MaxLen.
+func checkLength(f Field, raw string) string {
+	n := utf8.RuneCountInString(raw)
+	if f.LenInBytes {
+		n = len(raw)
+	}
+	switch {
+	case f.MinLen > 0 && n < f.MinLen:
+		return fmt.Sprintf("value length %d is below minimum %d", n, f.MinLen)
+	case f.MaxLen > 0 && n > f.MaxLen:
+		return fmt.Sprintf("value length %d is above maximum %d", n, f.MaxLen)
+	}
+	return ""
+}
+
+// checkByteLength returns a non-empty reason when want is non-zero and b does
+// not have exactly that many bytes.
+func checkByteLength(b []byte, want int) string {
+	if want != 0 && len(b) != want {
+		return fmt.Sprintf("decoded value is %d bytes, expected %d", len(b), want)
+	}
+	return ""
+}
+
+// checkFloatRange is the float64 counterpart of checkIntRange.
+func checkFloatRange(f Field, n float64, min, max *float64) string {
+	shown := boundedValue(f, strconv.FormatFloat(n, 'g', -1, 64))
+	switch {
+	case min != nil && max != nil && (n < *min || n > *max):
+		return fmt.Sprintf("value %s is out of range [%g, %g]", shown, *min, *max)
+	case min != nil && n < *min:
+		return fmt.Sprintf("value %s is below minimum %g", shown, *min)
+	case max != nil && n > *max:
+		return fmt.Sprintf("value %s is above maximum %g", shown, *max)
+	}
+	return ""
+}
+
+// checkDurationRange reports why d falls outside the optional inclusive
+// bounds, or returns "" if it is within them. The value is masked for Secret
+// fields.
+func checkDurationRange(f Field, d time.Duration, min, max *time.Duration) string {
+	shown := boundedValue(f, d.String())
+	switch {
+	case min != nil && d < *min:
+		return fmt.Sprintf("duration %s is below minimum %s", shown, *min)
+	case max != nil && d > *max:
+		return fmt.Sprintf("duration %s is above maximum %s", shown, *max)
+	}
+	return ""
+}
+
 // DurationResult is a convenience wrapper that returns a time.Duration from a
 // Result. It panics if the key was not declared or is not a KindDuration field.
 //
-// Example:
-//
-//	timeout := envvalidator.DurationResult(result, "TIMEOUT")
+// Deprecated: Use Result.Duration, which now returns a time.Duration.
 func DurationResult(r *Result, key string) time.Duration {
-	v, ok := r.Raw(key)
-	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q was not declared in the validator", key))
-	}
-	d, ok := v.(time.Duration)
-	if !ok {
-		panic(fmt.Sprintf("env-validator: key %q is not a duration field", key))
-	}
-	return d
+	return r.Duration(key)
 }
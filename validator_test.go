@@ -2,7 +2,16 @@ package envvalidator_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -236,3 +245,3145 @@ func TestValidationError_Error(t *testing.T) {
 		t.Errorf("unexpected error string: %s", e.Error())
 	}
 }
+
+func TestValidateMap_PortKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "HTTP_PORT", Kind: envvalidator.KindPort, Default: "8080"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"HTTP_PORT": "443"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("HTTP_PORT") != 443 {
+		t.Errorf("expected 443, got %d", result.Port("HTTP_PORT"))
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("HTTP_PORT") != 8080 {
+		t.Errorf("expected default 8080, got %d", result.Port("HTTP_PORT"))
+	}
+}
+
+func TestValidateMap_InvalidPort(t *testing.T) {
+	for _, input := range []string{"-1", "0", "65536", "70000", "http"} {
+		v := envvalidator.New(
+			envvalidator.Field{Key: "HTTP_PORT", Kind: envvalidator.KindPort, Required: true},
+		)
+		_, err := v.ValidateMap(context.Background(), map[string]string{"HTTP_PORT": input})
+		if err == nil {
+			t.Errorf("input %q: expected error for invalid port, got nil", input)
+			continue
+		}
+		if !strings.Contains(err.Error(), "must be between 1 and 65535") {
+			t.Errorf("input %q: expected range in error, got: %s", input, err.Error())
+		}
+	}
+}
+
+func TestValidateMap_IntegerBounds(t *testing.T) {
+	min, max := int64(1), int64(65535)
+	cases := []struct {
+		name    string
+		field   envvalidator.Field
+		input   string
+		wantErr string
+	}{
+		{"within both", envvalidator.Field{Min: &min, Max: &max}, "8080", ""},
+		{"above both", envvalidator.Field{Min: &min, Max: &max}, "100000", "value 100000 is out of range [1, 65535]"},
+		{"below both", envvalidator.Field{Min: &min, Max: &max}, "0", "value 0 is out of range [1, 65535]"},
+		{"inclusive min", envvalidator.Field{Min: &min, Max: &max}, "1", ""},
+		{"inclusive max", envvalidator.Field{Min: &min, Max: &max}, "65535", ""},
+		{"only min ok", envvalidator.Field{Min: &min}, "999999", ""},
+		{"only min fail", envvalidator.Field{Min: &min}, "-3", "value -3 is below minimum 1"},
+		{"only max ok", envvalidator.Field{Max: &max}, "-3", ""},
+		{"only max fail", envvalidator.Field{Max: &max}, "65536", "value 65536 is above maximum 65535"},
+	}
+	for _, tc := range cases {
+		f := tc.field
+		f.Key = "WORKERS"
+		f.Kind = envvalidator.KindInteger
+		v := envvalidator.New(f)
+		_, err := v.ValidateMap(context.Background(), map[string]string{"WORKERS": tc.input})
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestSchema_IntegerBounds(t *testing.T) {
+	min := int64(1)
+	v := envvalidator.New(
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Min: &min},
+	)
+	schema := v.Schema()
+	if schema[0].Min == nil || *schema[0].Min != 1 {
+		t.Errorf("expected min 1 in schema, got %v", schema[0].Min)
+	}
+	if schema[0].Max != nil {
+		t.Errorf("expected no max in schema, got %v", *schema[0].Max)
+	}
+}
+
+func TestValidateMap_EmailKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SUPPORT_EMAIL", Kind: envvalidator.KindEmail, Default: "help@example.com"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"SUPPORT_EMAIL": "  <ops@example.com> "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("SUPPORT_EMAIL") != "ops@example.com" {
+		t.Errorf("expected normalized ops@example.com, got %q", result.String("SUPPORT_EMAIL"))
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("SUPPORT_EMAIL") != "help@example.com" {
+		t.Errorf("expected default help@example.com, got %q", result.String("SUPPORT_EMAIL"))
+	}
+}
+
+func TestValidateMap_InvalidEmail(t *testing.T) {
+	inputs := []string{
+		"not-an-email",
+		"Ops Team <ops@example.com>",
+		"a@example.com, b@example.com",
+	}
+	for _, input := range inputs {
+		v := envvalidator.New(
+			envvalidator.Field{Key: "SUPPORT_EMAIL", Kind: envvalidator.KindEmail, Required: true},
+		)
+		_, err := v.ValidateMap(context.Background(), map[string]string{"SUPPORT_EMAIL": input})
+		if err == nil {
+			t.Errorf("input %q: expected error for invalid email, got nil", input)
+			continue
+		}
+		if !strings.Contains(err.Error(), "as an email address") {
+			t.Errorf("input %q: unexpected error: %s", input, err.Error())
+		}
+	}
+}
+
+func TestNewStrict_DuplicateKeys(t *testing.T) {
+	_, err := envvalidator.NewStrict(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger},
+		envvalidator.Field{Key: "HOST"},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort},
+		envvalidator.Field{Key: "HOST"},
+		envvalidator.Field{Key: "PORT"},
+	)
+	if err == nil {
+		t.Fatal("expected error for duplicate keys, got nil")
+	}
+	if err.Error() != "env-validator: duplicate field keys: PORT, HOST" {
+		t.Errorf("unexpected error string: %s", err.Error())
+	}
+}
+
+func TestNewStrict_UnsettableNames(t *testing.T) {
+	_, err := envvalidator.NewStrict(
+		envvalidator.Field{Key: "A=B"},
+		envvalidator.Field{Key: "TAB\tKEY"},
+		envvalidator.Field{Key: "port", Fallbacks: []string{"LEGACY=PORT"}},
+	)
+	if err == nil {
+		t.Fatal("expected an error for unsettable names")
+	}
+	for _, want := range []string{
+		`field "A=B": variable name "A=B" contains "=" and can never be set`,
+		`field "TAB\tKEY": variable name "TAB\tKEY" contains a control character`,
+		`field "port": variable name "LEGACY=PORT" contains "="`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), `"port" contains`) || strings.Contains(err.Error(), `variable name "port"`) {
+		t.Errorf("expected unconventional names to be left to Lint, got %v", err)
+	}
+}
+
+func TestNewStrict_UniqueKeys(t *testing.T) {
+	v, err := envvalidator.NewStrict(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Default: "8080"},
+		envvalidator.Field{Key: "HOST", Default: "localhost"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(v.Schema()) != 2 {
+		t.Errorf("expected 2 fields, got %d", len(v.Schema()))
+	}
+}
+
+func TestMustValidate_Success(t *testing.T) {
+	t.Setenv("ENVVALIDATOR_TEST_PORT", "9091")
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ENVVALIDATOR_TEST_PORT", Kind: envvalidator.KindInteger, Required: true},
+	)
+	result := v.MustValidate(context.Background())
+	if result.Integer("ENVVALIDATOR_TEST_PORT") != 9091 {
+		t.Errorf("expected 9091, got %d", result.Integer("ENVVALIDATOR_TEST_PORT"))
+	}
+}
+
+func TestMustValidate_PanicsOnError(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ENVVALIDATOR_TEST_UNSET", Required: true},
+	)
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic, got none")
+		}
+		if _, ok := r.(envvalidator.ValidationErrors); !ok {
+			t.Errorf("expected ValidationErrors panic value, got %T", r)
+		}
+	}()
+	v.MustValidate(context.Background())
+}
+
+func TestMustValidate_PanicsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ENVVALIDATOR_TEST_PORT", Default: "8080"},
+	)
+	defer func() {
+		if r := recover(); r != context.Canceled {
+			t.Errorf("expected context.Canceled panic, got %v", r)
+		}
+	}()
+	v.MustValidate(ctx)
+}
+
+func TestValidateMap_JSONKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "FEATURE_OVERRIDES", Kind: envvalidator.KindJSON, Default: "{}"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"FEATURE_OVERRIDES": `{"a":true,"n":[1,2]}`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m, ok := result.JSON("FEATURE_OVERRIDES").(map[string]any)
+	if !ok {
+		t.Fatalf("expected map[string]any, got %T", result.JSON("FEATURE_OVERRIDES"))
+	}
+	if m["a"] != true {
+		t.Errorf("expected a=true, got %v", m["a"])
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m, ok := result.JSON("FEATURE_OVERRIDES").(map[string]any); !ok || len(m) != 0 {
+		t.Errorf("expected empty default object, got %v", result.JSON("FEATURE_OVERRIDES"))
+	}
+}
+
+func TestValidateMap_InvalidJSON(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "FEATURE_OVERRIDES", Kind: envvalidator.KindJSON, Required: true},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"FEATURE_OVERRIDES": `{"a":tru}`})
+	if err == nil {
+		t.Fatal("expected error for malformed JSON, got nil")
+	}
+	if !strings.Contains(err.Error(), "at offset 9") {
+		t.Errorf("expected offset in error, got: %s", err.Error())
+	}
+}
+
+func TestResult_JSONPanicsOnStringField(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "NAME", Default: "x"})
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-JSON field")
+		}
+	}()
+	result.JSON("NAME")
+}
+
+func TestValidateMap_CaseInsensitiveAllowedValues(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{
+			Key:                    "LOG_LEVEL",
+			Default:                "info",
+			AllowedValues:          []string{"debug", "info", "warn", "error"},
+			CaseInsensitiveAllowed: true,
+		},
+	)
+	for _, input := range []string{"INFO", "Info", "info"} {
+		result, err := v.ValidateMap(context.Background(), map[string]string{"LOG_LEVEL": input})
+		if err != nil {
+			t.Errorf("input %q: unexpected error: %v", input, err)
+			continue
+		}
+		if result.String("LOG_LEVEL") != "info" {
+			t.Errorf("input %q: expected canonical info, got %q", input, result.String("LOG_LEVEL"))
+		}
+	}
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"LOG_LEVEL": "TRACE"}); err == nil {
+		t.Error("expected error for disallowed value, got nil")
+	}
+	if !v.Schema()[0].CaseInsensitiveAllowed {
+		t.Error("expected schema to report case-insensitive matching")
+	}
+}
+
+func TestValidateMap_AllowedValuesCaseSensitiveByDefault(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"info"}},
+	)
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"LOG_LEVEL": "INFO"}); err == nil {
+		t.Error("expected error for case mismatch, got nil")
+	}
+}
+
+func TestValidateMap_Pattern(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SERVICE_NAME", Pattern: `^[a-z][a-z0-9-]*$`, Default: "billing"},
+	)
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"SERVICE_NAME": "billing-api"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := v.ValidateMap(context.Background(), map[string]string{}); err != nil {
+		t.Errorf("unexpected error for default: %v", err)
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{"SERVICE_NAME": "Billing_API"})
+	if err == nil {
+		t.Fatal("expected error for pattern mismatch, got nil")
+	}
+	if !strings.Contains(err.Error(), `does not match required pattern "^[a-z][a-z0-9-]*$"`) {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if v.Schema()[0].Pattern != `^[a-z][a-z0-9-]*$` {
+		t.Errorf("expected pattern in schema, got %q", v.Schema()[0].Pattern)
+	}
+}
+
+func TestValidateMap_InvalidPattern(t *testing.T) {
+	fields := []envvalidator.Field{{Key: "SERVICE_NAME", Pattern: `[unclosed`}}
+	if _, err := envvalidator.NewStrict(fields...); err == nil || !strings.Contains(err.Error(), "invalid pattern") {
+		t.Errorf("expected NewStrict to reject invalid pattern, got %v", err)
+	}
+	_, err := envvalidator.New(fields...).ValidateMap(context.Background(), map[string]string{"SERVICE_NAME": "x"})
+	if err == nil || !strings.Contains(err.Error(), "not a valid regular expression") {
+		t.Errorf("expected validation error for invalid pattern, got %v", err)
+	}
+}
+
+func TestValidationErrors_ByKeyAndKeys(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Required: true},
+		envvalidator.Field{Key: "HOST", Default: "localhost"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"DATABASE_URL": "nope"})
+	verrs, ok := err.(envvalidator.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors, got %T", err)
+	}
+	if e := verrs.ByKey("DATABASE_URL"); e == nil || !strings.Contains(e.Reason, "absolute URL") {
+		t.Errorf("unexpected ByKey result: %v", e)
+	}
+	if e := verrs.ByKey("HOST"); e != nil {
+		t.Errorf("expected nil for passing key, got %v", e)
+	}
+	keys := verrs.Keys()
+	if len(keys) != 2 || keys[0] != "PORT" || keys[1] != "DATABASE_URL" {
+		t.Errorf("expected [PORT DATABASE_URL], got %v", keys)
+	}
+}
+
+func TestValidationErrors_ErrorsIsAndAs(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Default: "8080"},
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"info"}},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"PORT": "abc", "LOG_LEVEL": "trace"})
+	if err == nil {
+		t.Fatal("expected errors, got nil")
+	}
+	for _, sentinel := range []error{envvalidator.ErrRequiredMissing, envvalidator.ErrInvalidValue, envvalidator.ErrNotAllowed} {
+		if !errors.Is(err, sentinel) {
+			t.Errorf("expected errors.Is(err, %v) to be true", sentinel)
+		}
+	}
+	var single *envvalidator.ValidationError
+	if !errors.As(err, &single) {
+		t.Fatal("expected errors.As to find a *ValidationError")
+	}
+	if single.Key != "DATABASE_URL" {
+		t.Errorf("expected first error for DATABASE_URL, got %s", single.Key)
+	}
+	var batch envvalidator.ValidationErrors
+	if !errors.As(err, &batch) || len(batch) != 3 {
+		t.Errorf("expected errors.As to find ValidationErrors with 3 entries, got %v", batch)
+	}
+}
+
+func TestValidateMap_CIDRKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ALLOWED_CIDR", Kind: envvalidator.KindCIDR, Default: "127.0.0.0/8"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"ALLOWED_CIDR": "10.1.2.3/8"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.CIDR("ALLOWED_CIDR").String(); got != "10.0.0.0/8" {
+		t.Errorf("expected 10.0.0.0/8, got %s", got)
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.CIDR("ALLOWED_CIDR").String(); got != "127.0.0.0/8" {
+		t.Errorf("expected default 127.0.0.0/8, got %s", got)
+	}
+}
+
+func TestValidateMap_InvalidCIDR(t *testing.T) {
+	cases := map[string]string{
+		"10.0.0.0":     "missing /prefix length",
+		"10.0.0.300/8": "invalid IP address",
+		"10.0.0.0/33":  "invalid prefix length",
+	}
+	for input, want := range cases {
+		v := envvalidator.New(
+			envvalidator.Field{Key: "ALLOWED_CIDR", Kind: envvalidator.KindCIDR, Required: true},
+		)
+		_, err := v.ValidateMap(context.Background(), map[string]string{"ALLOWED_CIDR": input})
+		if err == nil {
+			t.Errorf("input %q: expected error, got nil", input)
+			continue
+		}
+		if !strings.Contains(err.Error(), "as a CIDR block: "+want) {
+			t.Errorf("input %q: expected %q in error, got: %s", input, want, err.Error())
+		}
+	}
+}
+
+func TestValidateMap_IPKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "BIND_ADDR", Kind: envvalidator.KindIP, Default: "127.0.0.1"},
+	)
+	for _, input := range []string{"10.0.0.1", "::1", "2001:db8::1"} {
+		result, err := v.ValidateMap(context.Background(), map[string]string{"BIND_ADDR": input})
+		if err != nil {
+			t.Errorf("input %q: unexpected error: %v", input, err)
+			continue
+		}
+		if !result.IP("BIND_ADDR").Equal(net.ParseIP(input)) {
+			t.Errorf("input %q: got %v", input, result.IP("BIND_ADDR"))
+		}
+	}
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IP("BIND_ADDR").String() != "127.0.0.1" {
+		t.Errorf("expected default 127.0.0.1, got %v", result.IP("BIND_ADDR"))
+	}
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"BIND_ADDR": "localhost"}); err == nil {
+		t.Error("expected error for hostname, got nil")
+	}
+}
+
+func TestValidateMap_IPVersion(t *testing.T) {
+	cases := []struct {
+		version int
+		input   string
+		wantErr string
+	}{
+		{4, "10.0.0.1", ""},
+		{4, "::1", "expected IPv4"},
+		{4, "::ffff:10.0.0.1", "expected IPv4"},
+		{6, "::1", ""},
+		{6, "10.0.0.1", "expected IPv6"},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(
+			envvalidator.Field{Key: "BIND_ADDR", Kind: envvalidator.KindIP, IPVersion: tc.version},
+		)
+		_, err := v.ValidateMap(context.Background(), map[string]string{"BIND_ADDR": tc.input})
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("v%d %q: unexpected error: %v", tc.version, tc.input, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("v%d %q: expected %q, got %v", tc.version, tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestValidateEnviron(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Required: true},
+		envvalidator.Field{Key: "DSN", Required: true},
+		envvalidator.Field{Key: "MODE", Default: "dev"},
+	)
+	result, err := v.ValidateEnviron(context.Background(), []string{
+		"PORT=9090",
+		"DSN=user=app password=x",
+		"GARBAGE",
+		"MODE=prod",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Integer("PORT") != 9090 {
+		t.Errorf("expected 9090, got %d", result.Integer("PORT"))
+	}
+	if result.String("DSN") != "user=app password=x" {
+		t.Errorf("expected value split on first '=', got %q", result.String("DSN"))
+	}
+	if result.String("MODE") != "prod" {
+		t.Errorf("expected prod, got %q", result.String("MODE"))
+	}
+}
+
+func TestValidateMap_SecretValuesRedactedInErrors(t *testing.T) {
+	const secret = "s3cr3t-Value"
+	cases := []envvalidator.Field{
+		{Key: "API_KEY", Kind: envvalidator.KindInteger, Secret: true},
+		{Key: "API_KEY", Kind: envvalidator.KindURL, Secret: true},
+		{Key: "API_KEY", Kind: envvalidator.KindJSON, Secret: true},
+		{Key: "API_KEY", Kind: envvalidator.KindCIDR, Secret: true},
+		{Key: "API_KEY", AllowedValues: []string{"other"}, Secret: true},
+		{Key: "API_KEY", Pattern: `^[0-9]+$`, Secret: true},
+	}
+	for _, f := range cases {
+		_, err := envvalidator.New(f).ValidateMap(context.Background(), map[string]string{"API_KEY": secret})
+		if err == nil {
+			t.Errorf("kind %q: expected error, got nil", f.Kind)
+			continue
+		}
+		if strings.Contains(err.Error(), "s3cr3t") {
+			t.Errorf("kind %q: secret leaked into error: %s", f.Kind, err.Error())
+		}
+		if !strings.Contains(err.Error(), "<redacted>") {
+			t.Errorf("kind %q: expected <redacted> placeholder, got: %s", f.Kind, err.Error())
+		}
+	}
+}
+
+func TestSchemaAndResult_SecretMasked(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "API_KEY", Default: "dev-key", Secret: true},
+		envvalidator.Field{Key: "REGION", Default: "us-east-1"},
+	)
+	schema := v.Schema()
+	if schema[0].Default != "<redacted>" || !schema[0].Secret {
+		t.Errorf("expected masked secret default, got %+v", schema[0])
+	}
+	result, err := v.ValidateMap(context.Background(), map[string]string{"API_KEY": "prod-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("API_KEY") != "prod-key" {
+		t.Errorf("expected accessor to return real value, got %q", result.String("API_KEY"))
+	}
+	dump := result.Redacted()
+	if dump["API_KEY"] != "<redacted>" || dump["REGION"] != "us-east-1" {
+		t.Errorf("unexpected redacted dump: %v", dump)
+	}
+}
+
+func TestValidateMap_ListKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "CORS_ORIGINS", Kind: envvalidator.KindList, ElementKind: envvalidator.KindURL, Default: "http://localhost"},
+		envvalidator.Field{Key: "SHARDS", Kind: envvalidator.KindList, ElementKind: envvalidator.KindInteger, Delimiter: ";", Default: "1"},
+		envvalidator.Field{Key: "LEVELS", Kind: envvalidator.KindList, ElementKind: envvalidator.KindEnum, AllowedValues: []string{"debug", "info"}, Default: "info"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{
+		"CORS_ORIGINS": "https://a.com,https://b.com",
+		"SHARDS":       "1; 2;3",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	origins := result.Strings("CORS_ORIGINS")
+	if len(origins) != 2 || origins[0] != "https://a.com" || origins[1] != "https://b.com" {
+		t.Errorf("unexpected origins: %v", origins)
+	}
+	shards := result.Integers("SHARDS")
+	if len(shards) != 3 || shards[1] != 2 {
+		t.Errorf("unexpected shards: %v", shards)
+	}
+	if levels := result.Strings("LEVELS"); len(levels) != 1 || levels[0] != "info" {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Strings("CORS_ORIGINS"); len(got) != 1 || got[0] != "http://localhost" {
+		t.Errorf("expected default origin, got %v", got)
+	}
+}
+
+func TestValidateMap_InvalidListElement(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SHARDS", Kind: envvalidator.KindList, ElementKind: envvalidator.KindInteger, Required: true},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"SHARDS": "1,x,y"})
+	if err == nil {
+		t.Fatal("expected error for invalid element, got nil")
+	}
+	if !strings.Contains(err.Error(), `element 1: cannot parse "x" as an integer`) {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+}
+
+func TestValidateMap_ListEmptyElements(t *testing.T) {
+	strict := envvalidator.New(envvalidator.Field{Key: "TAGS", Kind: envvalidator.KindList})
+	_, err := strict.ValidateMap(context.Background(), map[string]string{"TAGS": "a,b,"})
+	if err == nil || !strings.Contains(err.Error(), "element 2 is empty") {
+		t.Errorf("expected empty element error, got %v", err)
+	}
+	lenient := envvalidator.New(envvalidator.Field{Key: "TAGS", Kind: envvalidator.KindList, DropEmpty: true})
+	result, err := lenient.ValidateMap(context.Background(), map[string]string{"TAGS": "a,,b,"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Strings("TAGS"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected [a b], got %v", got)
+	}
+}
+
+func TestValidateMap_ListTrimElements(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "TAGS", Kind: envvalidator.KindList, TrimElements: true, DropEmpty: true})
+	result, err := v.ValidateMap(context.Background(), map[string]string{"TAGS": "A, ,B,"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Strings("TAGS"); !reflect.DeepEqual(got, []string{"A", "B"}) {
+		t.Errorf("expected [A B], got %q", got)
+	}
+
+	untrimmed := envvalidator.New(envvalidator.Field{Key: "TAGS", Kind: envvalidator.KindList})
+	result, err = untrimmed.ValidateMap(context.Background(), map[string]string{"TAGS": "A, B"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Strings("TAGS"); !reflect.DeepEqual(got, []string{"A", " B"}) {
+		t.Errorf("expected elements kept as written without TrimElements, got %q", got)
+	}
+
+	bounded := envvalidator.New(envvalidator.Field{Key: "CODES", Kind: envvalidator.KindList, TrimElements: true, MaxLen: 2})
+	if _, err := bounded.ValidateMap(context.Background(), map[string]string{"CODES": " us , eu "}); err != nil {
+		t.Errorf("expected MaxLen to apply to trimmed elements, got %v", err)
+	}
+}
+
+func TestValidateMap_CustomValidate(t *testing.T) {
+	errOdd := errors.New("port must be even")
+	var seen []string
+	v := envvalidator.New(
+		envvalidator.Field{
+			Key:     "PORT",
+			Kind:    envvalidator.KindInteger,
+			Default: "8080",
+			Validate: func(raw string) error {
+				seen = append(seen, raw)
+				if raw[len(raw)-1]%2 == 1 {
+					return errOdd
+				}
+				return nil
+			},
+		},
+	)
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"PORT": "9090"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{"PORT": "9091"})
+	if err == nil {
+		t.Fatal("expected custom validation error, got nil")
+	}
+	if !strings.Contains(err.Error(), `field "PORT": port must be even`) {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if !errors.Is(err, errOdd) || !errors.Is(err, envvalidator.ErrInvalidValue) {
+		t.Error("expected error to wrap both the custom error and ErrInvalidValue")
+	}
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"PORT": "abc"}); err == nil {
+		t.Error("expected parse error, got nil")
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected custom validator to run only after successful parsing, ran for %v", seen)
+	}
+}
+
+func TestNewWithPrefix(t *testing.T) {
+	v := envvalidator.NewWithPrefix("BILLING_",
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{
+		"BILLING_PORT":         "9000",
+		"BILLING_DATABASE_URL": "postgres://db/billing",
+		"PORT":                 "1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("PORT") != 9000 {
+		t.Errorf("expected 9000 from BILLING_PORT, got %d", result.Port("PORT"))
+	}
+	if result.String("DATABASE_URL") != "postgres://db/billing" {
+		t.Errorf("unexpected DATABASE_URL: %s", result.String("DATABASE_URL"))
+	}
+
+	_, err = v.ValidateMap(context.Background(), map[string]string{"DATABASE_URL": "postgres://db/other"})
+	verrs, ok := err.(envvalidator.ValidationErrors)
+	if !ok || verrs.ByKey("BILLING_DATABASE_URL") == nil {
+		t.Errorf("expected error for BILLING_DATABASE_URL, got %v", err)
+	}
+
+	schema := v.Schema()
+	if schema[0].Key != "BILLING_PORT" || schema[1].Key != "BILLING_DATABASE_URL" {
+		t.Errorf("expected prefixed schema keys, got %s and %s", schema[0].Key, schema[1].Key)
+	}
+}
+
+func TestValidate_WithPrefixReadsProcessEnv(t *testing.T) {
+	t.Setenv("ENVVALIDATOR_TEST_PORT", "7070")
+	v := envvalidator.NewWithPrefix("ENVVALIDATOR_TEST_",
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Required: true},
+	)
+	result, err := v.Validate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("PORT") != 7070 {
+		t.Errorf("expected 7070, got %d", result.Port("PORT"))
+	}
+}
+
+func TestResult_MarshalJSON(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "2m"},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Default: "8080"},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean, Default: "true"},
+		envvalidator.Field{Key: "NETWORK", Kind: envvalidator.KindCIDR, Default: "10.0.0.0/8"},
+		envvalidator.Field{Key: "API_KEY", Default: "super-secret", Secret: true},
+		envvalidator.Field{Key: "APP_VERSION", Kind: envvalidator.KindSemver, Default: "1.2.3-rc.1+build.5"},
+		envvalidator.Field{Key: "SIGNING_KEY", Kind: envvalidator.KindHex, Default: "DEADBEEF"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	expected := map[string]any{
+		"API_KEY":     "<redacted>",
+		"APP_VERSION": "1.2.3-rc.1+build.5",
+		"DEBUG":       true,
+		"NETWORK":     "10.0.0.0/8",
+		"PORT":        float64(8080),
+		"SIGNING_KEY": "deadbeef",
+		"TIMEOUT":     "2m0s",
+	}
+	if len(decoded) != len(expected) {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+	for k, want := range expected {
+		if decoded[k] != want {
+			t.Errorf("key %s: expected %v, got %v", k, want, decoded[k])
+		}
+	}
+	normalized, err := v.Normalize(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected normalize error: %v", err)
+	}
+	if decoded["SIGNING_KEY"] != normalized["SIGNING_KEY"] {
+		t.Errorf("expected JSON hex %v to match Normalize %q", decoded["SIGNING_KEY"], normalized["SIGNING_KEY"])
+	}
+}
+
+func TestValidateMap_RequiredIf(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "AUTH_MODE", Default: "none", AllowedValues: []string{"none", "oauth"}},
+		envvalidator.Field{
+			Key: "OAUTH_CLIENT_ID",
+			RequiredIf: func(env map[string]string) bool {
+				return env["AUTH_MODE"] == "oauth"
+			},
+			RequiredWhen: "AUTH_MODE=oauth",
+		},
+	)
+	if _, err := v.ValidateMap(context.Background(), map[string]string{}); err != nil {
+		t.Errorf("unexpected error when condition is false: %v", err)
+	}
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"AUTH_MODE": "oauth", "OAUTH_CLIENT_ID": "abc"}); err != nil {
+		t.Errorf("unexpected error when value is provided: %v", err)
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{"AUTH_MODE": "oauth"})
+	if err == nil {
+		t.Fatal("expected error when condition is true and value is missing, got nil")
+	}
+	if !strings.Contains(err.Error(), `field "OAUTH_CLIENT_ID": required when AUTH_MODE=oauth`) {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Error("expected error to wrap ErrRequiredMissing")
+	}
+}
+
+func TestValidateMap_Fallbacks(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Fallbacks: []string{"POD_PORT", "LEGACY_PORT"}, Default: "8080"},
+	)
+	cases := []struct {
+		env        map[string]string
+		wantPort   int
+		wantSource string
+	}{
+		{map[string]string{"PORT": "1000", "POD_PORT": "2000"}, 1000, "PORT"},
+		{map[string]string{"PORT": "", "POD_PORT": "2000", "LEGACY_PORT": "3000"}, 2000, "POD_PORT"},
+		{map[string]string{"LEGACY_PORT": "3000"}, 3000, "LEGACY_PORT"},
+		{map[string]string{}, 8080, ""},
+	}
+	for _, tc := range cases {
+		result, err := v.ValidateMap(context.Background(), tc.env)
+		if err != nil {
+			t.Errorf("env %v: unexpected error: %v", tc.env, err)
+			continue
+		}
+		if result.Port("PORT") != tc.wantPort || result.Source("PORT") != tc.wantSource {
+			t.Errorf("env %v: expected %d from %q, got %d from %q", tc.env, tc.wantPort, tc.wantSource, result.Port("PORT"), result.Source("PORT"))
+		}
+	}
+}
+
+func TestValidate_FallbacksReadProcessEnv(t *testing.T) {
+	t.Setenv("ENVVALIDATOR_TEST_POD_PORT", "6060")
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ENVVALIDATOR_TEST_UNSET_PORT", Kind: envvalidator.KindPort, Required: true, Fallbacks: []string{"ENVVALIDATOR_TEST_POD_PORT"}},
+	)
+	result, err := v.Validate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("ENVVALIDATOR_TEST_UNSET_PORT") != 6060 {
+		t.Errorf("expected 6060, got %d", result.Port("ENVVALIDATOR_TEST_UNSET_PORT"))
+	}
+}
+
+func TestValidateMap_Base64Kind(t *testing.T) {
+	cases := []struct {
+		field envvalidator.Field
+		input string
+		want  string
+	}{
+		{envvalidator.Field{}, "aGVsbG8=", "hello"},
+		{envvalidator.Field{}, "aGVsbG8", "hello"},
+		{envvalidator.Field{Base64URL: true}, "-_8", "\xfb\xff"},
+		{envvalidator.Field{StrictBase64: true}, "aGVsbG8=", "hello"},
+	}
+	for _, tc := range cases {
+		f := tc.field
+		f.Key, f.Kind, f.Required = "TLS_CERT_B64", envvalidator.KindBase64, true
+		result, err := envvalidator.New(f).ValidateMap(context.Background(), map[string]string{"TLS_CERT_B64": tc.input})
+		if err != nil {
+			t.Errorf("input %q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if string(result.Bytes("TLS_CERT_B64")) != tc.want {
+			t.Errorf("input %q: expected %q, got %q", tc.input, tc.want, result.Bytes("TLS_CERT_B64"))
+		}
+	}
+
+	v := envvalidator.New(envvalidator.Field{Key: "TLS_CERT_B64", Kind: envvalidator.KindBase64, Default: "ZGVm"})
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(result.Bytes("TLS_CERT_B64")) != "def" {
+		t.Errorf("expected default def, got %q", result.Bytes("TLS_CERT_B64"))
+	}
+}
+
+func TestValidateMap_InvalidBase64(t *testing.T) {
+	cases := []struct {
+		field envvalidator.Field
+		input string
+	}{
+		{envvalidator.Field{}, "not base64!"},
+		{envvalidator.Field{}, "-_8"},
+		{envvalidator.Field{StrictBase64: true}, "aGVsbG8"},
+		{envvalidator.Field{StrictBase64: true}, "aGVsbG9="},
+	}
+	for _, tc := range cases {
+		f := tc.field
+		f.Key, f.Kind = "TLS_CERT_B64", envvalidator.KindBase64
+		_, err := envvalidator.New(f).ValidateMap(context.Background(), map[string]string{"TLS_CERT_B64": tc.input})
+		if err == nil {
+			t.Errorf("input %q: expected error, got nil", tc.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), "as base64") {
+			t.Errorf("input %q: unexpected error: %s", tc.input, err.Error())
+		}
+	}
+}
+
+func TestValidatePartial(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindInteger, Default: "8080"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean, Default: "false"},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Required: true},
+	)
+	result, errs := v.ValidatePartial(context.Background(), map[string]string{"WORKERS": "many"})
+	if result == nil {
+		t.Fatal("expected a partial result, got nil")
+	}
+	if result.Integer("PORT") != 8080 || result.Boolean("DEBUG") {
+		t.Errorf("unexpected partial values: PORT=%d DEBUG=%v", result.Integer("PORT"), result.Boolean("DEBUG"))
+	}
+	if _, ok := result.Raw("WORKERS"); ok {
+		t.Error("expected failed field to be absent from the partial result")
+	}
+	keys := errs.Keys()
+	if len(keys) != 2 || keys[0] != "DATABASE_URL" || keys[1] != "WORKERS" {
+		t.Errorf("expected errors for [DATABASE_URL WORKERS], got %v", keys)
+	}
+}
+
+func TestValidatePartial_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	v := envvalidator.New(envvalidator.Field{Key: "PORT", Default: "8080"})
+	result, errs := v.ValidatePartial(ctx, map[string]string{})
+	if result == nil {
+		t.Fatal("expected a non-nil result")
+	}
+	if !errors.Is(errs, context.Canceled) {
+		t.Errorf("expected errors to report context.Canceled, got %v", errs)
+	}
+}
+
+func TestValidateMap_DeprecatedWarnings(t *testing.T) {
+	var got []string
+	v := envvalidator.New(
+		envvalidator.Field{Key: "OLD_DB_URL", Deprecated: "use DATABASE_URL instead"},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Default: "postgres://localhost/db"},
+	)
+	v.OnWarning = func(key, msg string) {
+		got = append(got, key+": "+msg)
+	}
+	result, err := v.ValidateMap(context.Background(), map[string]string{"OLD_DB_URL": "postgres://legacy/db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("OLD_DB_URL") != "postgres://legacy/db" {
+		t.Errorf("expected deprecated variable to still be validated, got %q", result.String("OLD_DB_URL"))
+	}
+	want := "OLD_DB_URL is deprecated: use DATABASE_URL instead"
+	if len(got) != 1 || got[0] != "OLD_DB_URL: "+want {
+		t.Errorf("unexpected OnWarning calls: %v", got)
+	}
+	if w := result.Warnings(); len(w) != 1 || w[0] != want {
+		t.Errorf("unexpected Result.Warnings: %v", w)
+	}
+	if v.Schema()[0].Deprecated != "use DATABASE_URL instead" {
+		t.Errorf("expected deprecation in schema, got %q", v.Schema()[0].Deprecated)
+	}
+
+	got = nil
+	result, err = v.ValidateMap(context.Background(), map[string]string{"DATABASE_URL": "postgres://new/db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 || len(result.Warnings()) != 0 {
+		t.Errorf("expected no warnings when deprecated variable is unset, got %v", got)
+	}
+}
+
+func TestValidateMap_UUIDKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DEFAULT_TENANT_ID", Kind: envvalidator.KindUUID, Default: "00000000-0000-0000-0000-000000000000"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"DEFAULT_TENANT_ID": "  6BA7B810-9DAD-11D1-80B4-00C04FD430C8 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.String("DEFAULT_TENANT_ID"); got != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("expected normalized lowercase UUID, got %q", got)
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.String("DEFAULT_TENANT_ID"); got != "00000000-0000-0000-0000-000000000000" {
+		t.Errorf("expected default nil UUID, got %q", got)
+	}
+}
+
+func TestValidateMap_InvalidUUID(t *testing.T) {
+	for _, input := range []string{"not-a-uuid", "6ba7b8109dad11d180b400c04fd430c8", "6ba7b810-9dad-11d1-80b4-00c04fd430cg", "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"} {
+		v := envvalidator.New(envvalidator.Field{Key: "TENANT", Kind: envvalidator.KindUUID, Required: true})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"TENANT": input})
+		if err == nil || !strings.Contains(err.Error(), "as a UUID") {
+			t.Errorf("input %q: expected UUID parse error, got %v", input, err)
+		}
+	}
+}
+
+func TestValidateMap_UUIDVersion(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "TENANT", Kind: envvalidator.KindUUID, UUIDVersion: 4})
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"TENANT": "f47ac10b-58cc-4372-a567-0e02b2c3d479"}); err != nil {
+		t.Errorf("unexpected error for v4 UUID: %v", err)
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{"TENANT": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"})
+	if err == nil || !strings.Contains(err.Error(), "not an RFC 4122 version 4 UUID") {
+		t.Errorf("expected version error for v1 UUID, got %v", err)
+	}
+}
+
+func TestValidateMap_SemverKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "MIN_CLIENT_VERSION", Kind: envvalidator.KindSemver, Default: "1.0.0"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"MIN_CLIENT_VERSION": "1.2.3-rc.1+build.5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	major, minor, patch, pre := result.Semver("MIN_CLIENT_VERSION")
+	if major != 1 || minor != 2 || patch != 3 || pre != "rc.1" {
+		t.Errorf("unexpected components: %d.%d.%d-%s", major, minor, patch, pre)
+	}
+	raw, _ := result.Raw("MIN_CLIENT_VERSION")
+	if sv := raw.(envvalidator.SemanticVersion); sv.Build != "build.5" || sv.String() != "1.2.3-rc.1+build.5" {
+		t.Errorf("unexpected SemanticVersion: %+v", sv)
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if major, _, _, _ := result.Semver("MIN_CLIENT_VERSION"); major != 1 {
+		t.Errorf("expected default major 1, got %d", major)
+	}
+}
+
+func TestValidateMap_InvalidSemver(t *testing.T) {
+	cases := map[string]string{
+		"1.2":       "expected MAJOR.MINOR.PATCH",
+		"1.x.3":     "invalid minor version",
+		"01.2.3":    "invalid major version",
+		"1.2.3-":    "invalid pre-release: empty identifier",
+		"1.2.3-01":  "invalid pre-release: numeric identifier has a leading zero",
+		"1.2.3+b_1": "invalid build metadata",
+	}
+	for input, want := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "VERSION", Kind: envvalidator.KindSemver, Required: true})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"VERSION": input})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("input %q: expected %q, got %v", input, want, err)
+		}
+	}
+}
+
+func TestValidateMap_SemverPartial(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "VERSION", Kind: envvalidator.KindSemver, AllowPartialVersion: true})
+	result, err := v.ValidateMap(context.Background(), map[string]string{"VERSION": "2.1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if major, minor, patch, _ := result.Semver("VERSION"); major != 2 || minor != 1 || patch != 0 {
+		t.Errorf("expected 2.1.0, got %d.%d.%d", major, minor, patch)
+	}
+}
+
+func TestValidateMap_Transform(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{
+			Key:           "LOG_LEVEL",
+			Default:       "INFO",
+			AllowedValues: []string{"debug", "info"},
+			Transform:     strings.ToLower,
+		},
+		envvalidator.Field{
+			Key:       "TOKEN",
+			Transform: func(s string) string { return strings.Trim(s, `'"`) },
+		},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"LOG_LEVEL": "DEBUG", "TOKEN": `"abc"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("LOG_LEVEL") != "debug" || result.String("TOKEN") != "abc" {
+		t.Errorf("unexpected transformed values: %q, %q", result.String("LOG_LEVEL"), result.String("TOKEN"))
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("LOG_LEVEL") != "info" {
+		t.Errorf("expected transformed default info, got %q", result.String("LOG_LEVEL"))
+	}
+}
+
+func TestValidateMap_TrimSpace(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SERVICE_NAME", Pattern: `^[a-z]+$`},
+	)
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"SERVICE_NAME": " billing "}); err == nil {
+		t.Fatal("expected pattern mismatch without TrimSpace, got nil")
+	}
+	v.TrimSpace = true
+	result, err := v.ValidateMap(context.Background(), map[string]string{"SERVICE_NAME": " billing "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("SERVICE_NAME") != "billing" {
+		t.Errorf("expected trimmed value, got %q", result.String("SERVICE_NAME"))
+	}
+}
+
+func TestValidateMapStrict_UnknownKeys(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Default: "postgres://localhost/db"},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080", Fallbacks: []string{"POD_PORT"}},
+	)
+	env := map[string]string{"DATABSE_URL": "postgres://typo/db", "POD_PORT": "9000", "ZZZ": "1", "AAA": "2"}
+	result, err := v.ValidateMapStrict(context.Background(), env)
+	var unknown *envvalidator.UnknownKeysError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownKeysError, got %T: %v", err, err)
+	}
+	if strings.Join(unknown.Keys, ",") != "AAA,DATABSE_URL,ZZZ" {
+		t.Errorf("unexpected unknown keys: %v", unknown.Keys)
+	}
+	if result == nil || result.Port("PORT") != 9000 {
+		t.Error("expected a usable result alongside the unknown-keys error")
+	}
+
+	if _, err := v.ValidateMapStrict(context.Background(), map[string]string{"PORT": "1"}); err != nil {
+		t.Errorf("unexpected error with only declared keys: %v", err)
+	}
+	_, err = v.ValidateMapStrict(context.Background(), map[string]string{"PORT": "x", "EXTRA": "1"})
+	if _, ok := err.(envvalidator.ValidationErrors); !ok {
+		t.Errorf("expected validation errors to take precedence, got %T", err)
+	}
+}
+
+func TestValidateMapStrict_PrefixScopesCheck(t *testing.T) {
+	v := envvalidator.NewWithPrefix("BILLING_", envvalidator.Field{Key: "PORT", Default: "8080"})
+	_, err := v.ValidateMapStrict(context.Background(), map[string]string{"BILLING_PORT": "1", "BILLING_PROT": "2", "HOME": "/root"})
+	var unknown *envvalidator.UnknownKeysError
+	if !errors.As(err, &unknown) || len(unknown.Keys) != 1 || unknown.Keys[0] != "BILLING_PROT" {
+		t.Errorf("expected only BILLING_PROT to be reported, got %v", err)
+	}
+}
+
+func TestResult_Duration(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "30s"},
+		envvalidator.Field{Key: "NAME", Default: "x"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"TIMEOUT": "1m30s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var d time.Duration = result.Duration("TIMEOUT")
+	if d != 90*time.Second {
+		t.Errorf("expected 1m30s, got %v", d)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-duration field")
+		}
+	}()
+	result.Duration("NAME")
+}
+
+func TestValidateMap_FilePathKind(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("a: 1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v := envvalidator.New(
+		envvalidator.Field{Key: "CONFIG_FILE", Kind: envvalidator.KindFilePath, MustExist: true, Default: file},
+		envvalidator.Field{Key: "CACHE_DIR", Kind: envvalidator.KindFilePath, MustBeDir: true, Default: dir},
+		envvalidator.Field{Key: "OUTPUT", Kind: envvalidator.KindFilePath, Default: "out/./report.txt"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("CONFIG_FILE") != file || result.String("CACHE_DIR") != dir {
+		t.Errorf("unexpected paths: %q, %q", result.String("CONFIG_FILE"), result.String("CACHE_DIR"))
+	}
+	wd, _ := os.Getwd()
+	if result.String("OUTPUT") != filepath.Join(wd, "out", "report.txt") {
+		t.Errorf("expected cleaned absolute path, got %q", result.String("OUTPUT"))
+	}
+}
+
+func TestValidateMap_InvalidFilePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		field envvalidator.Field
+		input string
+		want  string
+	}{
+		{envvalidator.Field{MustExist: true}, filepath.Join(dir, "missing.yaml"), "does not exist"},
+		{envvalidator.Field{MustExist: true}, dir, "is a directory; expected a file"},
+		{envvalidator.Field{MustBeDir: true}, file, "is a file; expected a directory"},
+	}
+	for _, tc := range cases {
+		f := tc.field
+		f.Key, f.Kind = "CONFIG_FILE", envvalidator.KindFilePath
+		_, err := envvalidator.New(f).ValidateMap(context.Background(), map[string]string{"CONFIG_FILE": tc.input})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("input %q: expected %q, got %v", tc.input, tc.want, err)
+		}
+	}
+}
+
+func TestValidateMap_Alias(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "databaseURL", Alias: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{
+		"DATABASE_URL": "postgres://localhost/db",
+		"databaseURL":  "postgres://ignored/db",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("databaseURL") != "postgres://localhost/db" {
+		t.Errorf("expected value from DATABASE_URL, got %q", result.String("databaseURL"))
+	}
+	schema := v.Schema()
+	if schema[0].Key != "DATABASE_URL" || schema[0].ResultKey != "databaseURL" {
+		t.Errorf("unexpected schema for aliased field: %+v", schema[0])
+	}
+	if schema[1].ResultKey != "" {
+		t.Errorf("expected no result key for plain field, got %q", schema[1].ResultKey)
+	}
+
+	_, err = v.ValidateMap(context.Background(), map[string]string{"databaseURL": "postgres://ignored/db"})
+	verrs, _ := err.(envvalidator.ValidationErrors)
+	if verrs.ByKey("DATABASE_URL") == nil {
+		t.Errorf("expected error naming DATABASE_URL, got %v", err)
+	}
+}
+
+func TestValidateMap_SecretIntegerRangeIsMasked(t *testing.T) {
+	max := int64(10)
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PIN", Kind: envvalidator.KindInteger, Secret: true, Max: &max},
+		envvalidator.Field{Key: "QUOTA", Kind: envvalidator.KindBytes, Secret: true, Max: &max},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"PIN": "987654", "QUOTA": "987654"})
+	if err == nil {
+		t.Fatal("expected range errors")
+	}
+	if strings.Contains(err.Error(), "987654") {
+		t.Errorf("error echoes a secret value: %v", err)
+	}
+	if !strings.Contains(err.Error(), "value <redacted> is above maximum 10") {
+		t.Errorf("expected a masked range error, got %v", err)
+	}
+}
+
+func TestValidateMap_SecretFloatRangeIsMasked(t *testing.T) {
+	max := 1.0
+	v := envvalidator.New(envvalidator.Field{Key: "RATIO", Kind: envvalidator.KindFloat, Secret: true, MaxFloat: &max})
+	_, err := v.ValidateMap(context.Background(), map[string]string{"RATIO": "42.125"})
+	if err == nil || strings.Contains(err.Error(), "42.125") {
+		t.Fatalf("expected a range error that does not echo the secret, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "value <redacted> is above maximum 1") {
+		t.Errorf("expected a masked range error, got %v", err)
+	}
+}
+
+func TestValidateMap_FloatRange(t *testing.T) {
+	min, max := 0.0, 1.0
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"0", ""},
+		{"0.0", ""},
+		{"1.0", ""},
+		{"0.25", ""},
+		{"-0.0001", "value -0.0001 is out of range [0, 1]"},
+		{"1.0001", "value 1.0001 is out of range [0, 1]"},
+		{"NaN", "is not a finite number"},
+		{"nan", "is not a finite number"},
+		{"Inf", "is not a finite number"},
+		{"+inf", "is not a finite number"},
+		{"-Infinity", "is not a finite number"},
+		{"INF", "is not a finite number"},
+	}
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SAMPLE_RATE", Kind: envvalidator.KindFloat, MinFloat: &min, MaxFloat: &max},
+	)
+	for _, tc := range cases {
+		_, err := v.ValidateMap(context.Background(), map[string]string{"SAMPLE_RATE": tc.input})
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", tc.input, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestValidateMap_DurationBounds(t *testing.T) {
+	min, max := 100*time.Millisecond, 60*time.Second
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"100ms", ""},
+		{"60s", ""},
+		{"5s", ""},
+		{"90ms", "duration 90ms is below minimum 100ms"},
+		{"5h", "duration 5h0m0s is above maximum 1m0s"},
+	}
+	v := envvalidator.New(
+		envvalidator.Field{Key: "HTTP_TIMEOUT", Kind: envvalidator.KindDuration, MinDuration: &min, MaxDuration: &max},
+	)
+	for _, tc := range cases {
+		_, err := v.ValidateMap(context.Background(), map[string]string{"HTTP_TIMEOUT": tc.input})
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", tc.input, err)
+			}
+			continue
+		}
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+
+	s := v.Schema()[0]
+	if s.MinDuration != "100ms" || s.MaxDuration != "1m0s" {
+		t.Errorf("unexpected schema bounds: %q, %q", s.MinDuration, s.MaxDuration)
+	}
+}
+
+func TestValidateMap_SecretDurationBoundsAreMasked(t *testing.T) {
+	max := time.Second
+	v := envvalidator.New(envvalidator.Field{Key: "TOKEN_TTL", Kind: envvalidator.KindDuration, Secret: true, MaxDuration: &max})
+	_, err := v.ValidateMap(context.Background(), map[string]string{"TOKEN_TTL": "7h13m"})
+	if err == nil || strings.Contains(err.Error(), "7h13m") {
+		t.Fatalf("expected a bounds error that does not echo the secret, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "duration <redacted> is above maximum 1s") {
+		t.Errorf("expected a masked bounds error, got %v", err)
+	}
+}
+
+func TestValidateMap_FloatRejectsNonFiniteWithoutBounds(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "RATIO", Kind: envvalidator.KindFloat})
+	_, err := v.ValidateMap(context.Background(), map[string]string{"RATIO": "nAn"})
+	if !errors.Is(err, envvalidator.ErrInvalidValue) {
+		t.Fatalf("expected ErrInvalidValue, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"nAn" is not a finite number`) {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestValidateMap_OnMissing(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+		envvalidator.Field{Key: "HOST", Default: "localhost"},
+		envvalidator.Field{Key: "TRACE_ID"},
+		envvalidator.Field{Key: "TOKEN", Required: true},
+	)
+	var got []string
+	v.OnMissing = func(key string, usedDefault bool) {
+		got = append(got, fmt.Sprintf("%s:%t", key, usedDefault))
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{"APP_HOST": "example.com"})
+	if !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Fatalf("expected ErrRequiredMissing, got %v", err)
+	}
+	want := []string{"APP_PORT:true", "APP_TRACE_ID:false", "APP_TOKEN:false"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("unexpected OnMissing calls: got %v, want %v", got, want)
+	}
+}
+
+func TestValidateMap_HexKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SIGNING_KEY", Kind: envvalidator.KindHex, Required: true, Secret: true},
+		envvalidator.Field{Key: "SALT", Kind: envvalidator.KindHex, Default: "c0ffee"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"SIGNING_KEY": " DEADbeef "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Bytes("SIGNING_KEY"); string(got) != "\xde\xad\xbe\xef" {
+		t.Errorf("unexpected signing key bytes: %x", got)
+	}
+	if got := result.Bytes("SALT"); string(got) != "\xc0\xff\xee" {
+		t.Errorf("expected default salt bytes, got %x", got)
+	}
+	diffs, err := v.Diff(context.Background(),
+		map[string]string{"SIGNING_KEY": "00"},
+		map[string]string{"SIGNING_KEY": "00", "SALT": "C0FFEE"})
+	if err != nil {
+		t.Fatalf("unexpected diff error: %v", err)
+	}
+	if diffs[1].NewValue != "c0ffee" || diffs[1].Changed {
+		t.Errorf("expected hex text form, got %+v", diffs[1])
+	}
+}
+
+func TestValidateMap_InvalidHex(t *testing.T) {
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"abc", "odd number of digits"},
+		{"zz", `cannot parse "zz" as hex`},
+		{"00ff", "decoded value is 2 bytes, expected 4"},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "SIGNING_KEY", Kind: envvalidator.KindHex, ByteLength: 4})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"SIGNING_KEY": tc.input})
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("input %q: expected ErrInvalidValue containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestValidateMap_Base64ByteLength(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "TLS_CERT_B64", Kind: envvalidator.KindBase64, ByteLength: 4})
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"TLS_CERT_B64": "aGVsbA=="}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{"TLS_CERT_B64": "aGVsbG8="})
+	if err == nil || !strings.Contains(err.Error(), "decoded value is 5 bytes, expected 4") {
+		t.Errorf("expected byte length error, got %v", err)
+	}
+}
+
+func TestValidateMap_ValidateCtx(t *testing.T) {
+	var gotRaw string
+	v := envvalidator.New(envvalidator.Field{
+		Key: "UPSTREAM_HOST",
+		ValidateCtx: func(ctx context.Context, raw string) error {
+			gotRaw = raw
+			if raw == "unreachable" {
+				return errors.New("host is unreachable")
+			}
+			return nil
+		},
+	})
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"UPSTREAM_HOST": "api.internal"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotRaw != "api.internal" {
+		t.Errorf("expected hook to receive raw value, got %q", gotRaw)
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{"UPSTREAM_HOST": "unreachable"})
+	if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), "host is unreachable") {
+		t.Errorf("expected ErrInvalidValue with hook message, got %v", err)
+	}
+}
+
+func TestValidateMap_ValidateCtxCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	laterCalled := false
+	v := envvalidator.New(
+		envvalidator.Field{Key: "UPSTREAM_HOST", ValidateCtx: func(ctx context.Context, raw string) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		envvalidator.Field{Key: "LATER", Validate: func(string) error {
+			laterCalled = true
+			return nil
+		}},
+	)
+	_, err := v.ValidateMap(ctx, map[string]string{"UPSTREAM_HOST": "api.internal"})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, envvalidator.ErrInvalidValue) {
+		t.Errorf("expected bare context.Canceled, got %v", err)
+	}
+	if laterCalled {
+		t.Error("expected validation to stop after the cancelled hook")
+	}
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	v2 := envvalidator.New(envvalidator.Field{Key: "UPSTREAM_HOST", ValidateCtx: func(context.Context, string) error {
+		cancel2()
+		return nil
+	}})
+	_, partialErrs := v2.ValidatePartial(ctx2, map[string]string{})
+	if partialErrs.ByKey("UPSTREAM_HOST") == nil || !errors.Is(partialErrs, context.Canceled) {
+		t.Errorf("expected cancellation naming UPSTREAM_HOST, got %v", partialErrs)
+	}
+}
+
+func TestValidateMap_ValidateCtxNotRunKeepsFieldError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	v := envvalidator.New(envvalidator.Field{
+		Key:  "PORT",
+		Kind: envvalidator.KindPort,
+		// Cancel while the field is being processed, before parsing fails.
+		Transform: func(raw string) string {
+			cancel()
+			return raw
+		},
+		ValidateCtx: func(context.Context, string) error {
+			t.Error("hook should not run for an unparseable value")
+			return nil
+		},
+	})
+	_, err := v.ValidateMap(ctx, map[string]string{"PORT": "http"})
+	if !errors.Is(err, envvalidator.ErrInvalidValue) || errors.Is(err, context.Canceled) {
+		t.Errorf("expected the parse error to be kept, got %v", err)
+	}
+}
+
+func TestValidateMap_TimezoneKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SCHEDULER_TZ", Kind: envvalidator.KindTimezone, Required: true},
+		envvalidator.Field{Key: "REPORT_TZ", Kind: envvalidator.KindTimezone, Default: "UTC"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"SCHEDULER_TZ": " America/New_York "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Location("SCHEDULER_TZ").String(); got != "America/New_York" {
+		t.Errorf("expected America/New_York, got %q", got)
+	}
+	if result.Location("REPORT_TZ") != time.UTC {
+		t.Errorf("expected default UTC, got %v", result.Location("REPORT_TZ"))
+	}
+	data, err := json.Marshal(result)
+	if err != nil || !strings.Contains(string(data), `"SCHEDULER_TZ":"America/New_York"`) {
+		t.Errorf("expected timezone name in JSON, got %s (%v)", data, err)
+	}
+}
+
+func TestValidateMap_InvalidTimezone(t *testing.T) {
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"Mars/Olympus_Mons", `cannot load "Mars/Olympus_Mons" as an IANA timezone`},
+		{"   ", "timezone is empty"},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "SCHEDULER_TZ", Kind: envvalidator.KindTimezone})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"SCHEDULER_TZ": tc.input})
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("input %q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestValidateLayered(t *testing.T) {
+	t.Setenv("ENVVALIDATOR_TEST_LAYER_PORT", "7070")
+	t.Setenv("ENVVALIDATOR_TEST_LAYER_HOST", "")
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ENVVALIDATOR_TEST_LAYER_PORT", Kind: envvalidator.KindPort, Required: true},
+		envvalidator.Field{Key: "ENVVALIDATOR_TEST_LAYER_HOST", Required: true},
+	)
+	base := map[string]string{
+		"ENVVALIDATOR_TEST_LAYER_PORT": "8080",
+		"ENVVALIDATOR_TEST_LAYER_HOST": "file.example.com",
+	}
+	result, err := v.ValidateLayered(context.Background(), base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("ENVVALIDATOR_TEST_LAYER_PORT") != 7070 {
+		t.Errorf("expected process env to win, got %d", result.Port("ENVVALIDATOR_TEST_LAYER_PORT"))
+	}
+	if result.String("ENVVALIDATOR_TEST_LAYER_HOST") != "file.example.com" {
+		t.Errorf("expected empty env var to leave base value, got %q", result.String("ENVVALIDATOR_TEST_LAYER_HOST"))
+	}
+	if base["ENVVALIDATOR_TEST_LAYER_PORT"] != "8080" {
+		t.Error("ValidateLayered modified base")
+	}
+}
+
+func TestGet(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "NAME", Default: "svc"},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Default: "4"},
+		envvalidator.Field{Key: "RATIO", Kind: envvalidator.KindFloat, Default: "0.5"},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean, Default: "true"},
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "2m"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s, err := envvalidator.Get[string](result, "NAME"); err != nil || s != "svc" {
+		t.Errorf("string: got %q, %v", s, err)
+	}
+	if n, err := envvalidator.Get[int64](result, "WORKERS"); err != nil || n != 4 {
+		t.Errorf("int64: got %d, %v", n, err)
+	}
+	if f, err := envvalidator.Get[float64](result, "RATIO"); err != nil || f != 0.5 {
+		t.Errorf("float64: got %g, %v", f, err)
+	}
+	if b, err := envvalidator.Get[bool](result, "DEBUG"); err != nil || !b {
+		t.Errorf("bool: got %t, %v", b, err)
+	}
+	if d, err := envvalidator.Get[time.Duration](result, "TIMEOUT"); err != nil || d != 2*time.Minute {
+		t.Errorf("duration: got %s, %v", d, err)
+	}
+
+	if _, err := envvalidator.Get[int](result, "WORKERS"); err == nil || !strings.Contains(err.Error(), `key "WORKERS" holds int64, not int`) {
+		t.Errorf("expected type mismatch error, got %v", err)
+	}
+	if _, err := envvalidator.Get[string](result, "MISSING"); err == nil || !strings.Contains(err.Error(), "was not declared") {
+		t.Errorf("expected undeclared key error, got %v", err)
+	}
+}
+
+func TestValidationErrors_Sorted(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ZONE", Required: true},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Required: true},
+		envvalidator.Field{Key: "API_KEY", Required: true},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"PORT": "0"})
+	errs, ok := err.(envvalidator.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors, got %T", err)
+	}
+	sorted := errs.Sorted()
+	if got := strings.Join(sorted.Keys(), ","); got != "API_KEY,PORT,ZONE" {
+		t.Errorf("expected sorted keys, got %s", got)
+	}
+	if got := strings.Join(errs.Keys(), ","); got != "ZONE,PORT,API_KEY" {
+		t.Errorf("expected original order to be unchanged, got %s", got)
+	}
+}
+
+func TestValidateMap_AnyOf(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "CACHE_BACKEND", AnyOf: []envvalidator.Kind{envvalidator.KindURL, envvalidator.KindString}, Default: "memory"},
+		envvalidator.Field{Key: "LIMIT", AnyOf: []envvalidator.Kind{envvalidator.KindInteger, envvalidator.KindDuration}, Required: true},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"CACHE_BACKEND": "redis://cache:6379", "LIMIT": "30s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Kind("CACHE_BACKEND") != envvalidator.KindURL || result.String("CACHE_BACKEND") != "redis://cache:6379" {
+		t.Errorf("expected URL match, got %s %q", result.Kind("CACHE_BACKEND"), result.String("CACHE_BACKEND"))
+	}
+	if result.Kind("LIMIT") != envvalidator.KindDuration || result.Duration("LIMIT") != 30*time.Second {
+		t.Errorf("expected duration match, got %s", result.Kind("LIMIT"))
+	}
+
+	result, err = v.ValidateMap(context.Background(), map[string]string{"LIMIT": "100"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Kind("CACHE_BACKEND") != envvalidator.KindString || result.String("CACHE_BACKEND") != "memory" {
+		t.Errorf("expected default to match string, got %s %q", result.Kind("CACHE_BACKEND"), result.String("CACHE_BACKEND"))
+	}
+	if result.Kind("LIMIT") != envvalidator.KindInteger || result.Integer("LIMIT") != 100 {
+		t.Errorf("expected integer match, got %s", result.Kind("LIMIT"))
+	}
+
+	_, err = v.ValidateMap(context.Background(), map[string]string{"LIMIT": "lots"})
+	if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), `cannot parse "lots" as any of: integer, duration`) {
+		t.Errorf("expected error listing tried kinds, got %v", err)
+	}
+	if got := v.Schema()[1].AnyOf; len(got) != 2 || got[0] != "integer" || got[1] != "duration" {
+		t.Errorf("expected any_of in schema, got %v", got)
+	}
+}
+
+func TestValidateMap_CaptureAll(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Fallbacks: []string{"LEGACY_PORT"}},
+	)
+	env := map[string]string{"PORT": "8080", "LEGACY_PORT": "9090", "HOME": "/root", "GITHUB_TOKEN": "ghp_x"}
+
+	result, err := v.ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result.Raw("HOME"); ok {
+		t.Error("expected undeclared keys to be absent without CaptureAll")
+	}
+
+	v.CaptureAll = true
+	result, err = v.ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if raw, ok := result.Raw("HOME"); !ok || raw != "/root" {
+		t.Errorf("expected captured HOME, got %v, %t", raw, ok)
+	}
+	if raw, _ := result.Raw("PORT"); raw != 8080 {
+		t.Errorf("expected typed PORT, got %v", raw)
+	}
+	got := result.Undeclared()
+	if len(got) != 2 || got["HOME"] != "/root" || got["GITHUB_TOKEN"] != "<redacted>" {
+		t.Errorf("unexpected undeclared dump: %v", got)
+	}
+	if _, ok := result.Redacted()["HOME"]; ok {
+		t.Error("expected captured values to stay out of Redacted")
+	}
+}
+
+func TestValidate_CaptureAll(t *testing.T) {
+	t.Setenv("ENVVALIDATOR_TEST_CAPTURED", "yes")
+	v := envvalidator.New(envvalidator.Field{Key: "ENVVALIDATOR_TEST_UNSET_PORT", Kind: envvalidator.KindPort, Default: "8080"})
+	v.CaptureAll = true
+	result, err := v.Validate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Undeclared()["ENVVALIDATOR_TEST_CAPTURED"] != "yes" {
+		t.Error("expected Validate to capture the whole process environment")
+	}
+}
+
+func TestValidateMap_StringLength(t *testing.T) {
+	cases := []struct {
+		name    string
+		field   envvalidator.Field
+		input   string
+		wantErr string
+	}{
+		{"unset bounds", envvalidator.Field{}, "", ""},
+		{"at minimum", envvalidator.Field{MinLen: 4}, "abcd", ""},
+		{"below minimum", envvalidator.Field{MinLen: 32}, "0123456789", "value length 10 is below minimum 32"},
+		{"above maximum", envvalidator.Field{MaxLen: 3}, "abcd", "value length 4 is above maximum 3"},
+		{"runes by default", envvalidator.Field{MaxLen: 2}, "日本", ""},
+		{"bytes when requested", envvalidator.Field{MaxLen: 2, LenInBytes: true}, "日本", "value length 6 is above maximum 2"},
+	}
+	for _, tc := range cases {
+		f := tc.field
+		f.Key, f.Secret = "API_KEY", true
+		_, err := envvalidator.New(f).ValidateMap(context.Background(), map[string]string{"API_KEY": tc.input})
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	schema := envvalidator.New(envvalidator.Field{Key: "API_KEY", MinLen: 32, MaxLen: 64}).Schema()
+	if schema[0].MinLen != 32 || schema[0].MaxLen != 64 {
+		t.Errorf("expected length bounds in schema, got %+v", schema[0])
+	}
+}
+
+func TestValidateMap_BytesKind(t *testing.T) {
+	cases := []struct {
+		input string
+		want  int64
+	}{
+		{"512", 512},
+		{"512B", 512},
+		{"10MB", 10_000_000},
+		{"10 mb", 10_000_000},
+		{"1KB", 1000},
+		{"1KiB", 1024},
+		{"2gib", 2 << 30},
+		{"3TB", 3_000_000_000_000},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "MAX_UPLOAD_SIZE", Kind: envvalidator.KindBytes, Required: true})
+		result, err := v.ValidateMap(context.Background(), map[string]string{"MAX_UPLOAD_SIZE": tc.input})
+		if err != nil {
+			t.Errorf("input %q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got := result.Bytes64("MAX_UPLOAD_SIZE"); got != tc.want {
+			t.Errorf("input %q: expected %d, got %d", tc.input, tc.want, got)
+		}
+	}
+
+	v := envvalidator.New(envvalidator.Field{Key: "MAX_UPLOAD_SIZE", Kind: envvalidator.KindBytes, Default: "1MiB"})
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Bytes64("MAX_UPLOAD_SIZE") != 1<<20 {
+		t.Errorf("expected default 1MiB, got %d", result.Bytes64("MAX_UPLOAD_SIZE"))
+	}
+}
+
+func TestValidateMap_InvalidBytes(t *testing.T) {
+	max := int64(1 << 20)
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"-5MB", "size must not be negative"},
+		{"MB", "missing number"},
+		{"10XB", "unknown unit"},
+		{"1.5GB", "fractional sizes are not supported"},
+		{"9999999TiB", "size overflows int64"},
+		{"2MiB", "value 2097152 is above maximum 1048576"},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "MAX_UPLOAD_SIZE", Kind: envvalidator.KindBytes, Max: &max})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"MAX_UPLOAD_SIZE": tc.input})
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("input %q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "2m"},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean},
+		envvalidator.Field{Key: "ENDPOINT", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"debug", "info"}, CaseInsensitiveAllowed: true},
+		envvalidator.Field{Key: "TOKEN", Secret: true, Required: true},
+		envvalidator.Field{Key: "OPTIONAL"},
+	)
+	got, err := v.Normalize(context.Background(), map[string]string{
+		"APP_DEBUG":     "YES",
+		"APP_ENDPOINT":  "https://api.example.com/v1",
+		"APP_LOG_LEVEL": "INFO",
+		"APP_TOKEN":     "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"APP_TIMEOUT":   "2m0s",
+		"APP_DEBUG":     "true",
+		"APP_ENDPOINT":  "https://api.example.com/v1",
+		"APP_LOG_LEVEL": "info",
+		"APP_TOKEN":     "s3cret",
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d keys, got %v", len(want), got)
+	}
+	for k, w := range want {
+		if got[k] != w {
+			t.Errorf("%s: expected %q, got %q", k, w, got[k])
+		}
+	}
+
+	if _, err := v.Normalize(context.Background(), map[string]string{}); !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestValidateMap_BoolTokens(t *testing.T) {
+	tokens := map[string]bool{"on": true, "enabled": true, "off": false, "disabled": false}
+	cases := []struct {
+		input string
+		want  bool
+	}{
+		{"ON", true},
+		{"enabled", true},
+		{" Off ", false},
+		{"disabled", false},
+		{"yes", true},
+		{"0", false},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "FEATURE_X", Kind: envvalidator.KindBoolean, BoolTokens: tokens})
+		result, err := v.ValidateMap(context.Background(), map[string]string{"FEATURE_X": tc.input})
+		if err != nil {
+			t.Errorf("input %q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if result.Boolean("FEATURE_X") != tc.want {
+			t.Errorf("input %q: expected %t", tc.input, tc.want)
+		}
+	}
+
+	v := envvalidator.New(envvalidator.Field{Key: "FEATURE_X", Kind: envvalidator.KindBoolean, BoolTokens: tokens})
+	_, err := v.ValidateMap(context.Background(), map[string]string{"FEATURE_X": "maybe"})
+	want := "accepted values are true, false, 1, 0, yes, no, enabled, on, disabled, off"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error listing %q, got %v", want, err)
+	}
+
+	plain := envvalidator.New(envvalidator.Field{Key: "FEATURE_X", Kind: envvalidator.KindBoolean})
+	if _, err := plain.ValidateMap(context.Background(), map[string]string{"FEATURE_X": "on"}); err == nil {
+		t.Error("expected on to be rejected without BoolTokens")
+	}
+}
+
+func TestValidationError_KindAndValue(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Transform: strings.ToLower},
+		envvalidator.Field{Key: "API_KEY", Kind: envvalidator.KindHex, Secret: true},
+		envvalidator.Field{Key: "REGION", Required: true},
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"debug", "info"}},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"WORKERS": "ABC", "API_KEY": "zz-secret", "LOG_LEVEL": "trace"})
+	errs, ok := err.(envvalidator.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	cases := []struct {
+		key   string
+		kind  envvalidator.Kind
+		value string
+	}{
+		{"WORKERS", envvalidator.KindInteger, "abc"},
+		{"API_KEY", envvalidator.KindHex, "<redacted>"},
+		{"REGION", envvalidator.KindString, ""},
+		{"LOG_LEVEL", envvalidator.KindString, "trace"},
+	}
+	for _, tc := range cases {
+		e := errs.ByKey(tc.key)
+		if e == nil {
+			t.Errorf("%s: expected an error", tc.key)
+			continue
+		}
+		if e.Kind != tc.kind || e.Value != tc.value {
+			t.Errorf("%s: expected kind %s and value %q, got %s and %q", tc.key, tc.kind, tc.value, e.Kind, e.Value)
+		}
+	}
+	if got := errs.ByKey("WORKERS").Error(); got != `env-validator: field "WORKERS": cannot parse "abc" as an integer` {
+		t.Errorf("Error output changed: %s", got)
+	}
+}
+
+func TestValidateMap_FileSuffix(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "db_url")
+	if err := os.WriteFile(secretPath, []byte("postgres://file/db\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true, Secret: true},
+	)
+	env := map[string]string{"DATABASE_URL_FILE": secretPath}
+	if _, err := v.ValidateMap(context.Background(), env); !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Fatalf("expected _FILE to be ignored by default, got %v", err)
+	}
+
+	v.SupportFileSuffix = true
+	result, err := v.ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("DATABASE_URL") != "postgres://file/db" {
+		t.Errorf("expected file contents, got %q", result.String("DATABASE_URL"))
+	}
+	if result.Source("DATABASE_URL") != "DATABASE_URL_FILE" {
+		t.Errorf("expected source DATABASE_URL_FILE, got %q", result.Source("DATABASE_URL"))
+	}
+
+	env["DATABASE_URL"] = "postgres://direct/db"
+	result, err = v.ValidateMap(context.Background(), env)
+	if err != nil || result.String("DATABASE_URL") != "postgres://direct/db" {
+		t.Errorf("expected the variable itself to win, got %v, %v", result, err)
+	}
+
+	missing := filepath.Join(dir, "absent")
+	_, err = v.ValidateMap(context.Background(), map[string]string{"DATABASE_URL_FILE": missing})
+	if !errors.Is(err, os.ErrNotExist) || !errors.Is(err, envvalidator.ErrInvalidValue) {
+		t.Fatalf("expected a not-exist validation error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("cannot read DATABASE_URL_FILE path %q", missing)) {
+		t.Errorf("expected error naming the path, got %v", err)
+	}
+}
+
+func TestValidate_FileSuffixFromProcessEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("t0k3n\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ENVVALIDATOR_TEST_TOKEN_FILE", path)
+	v := envvalidator.New(envvalidator.Field{Key: "ENVVALIDATOR_TEST_TOKEN", Required: true})
+	v.SupportFileSuffix = true
+	result, err := v.Validate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("ENVVALIDATOR_TEST_TOKEN") != "t0k3n" {
+		t.Errorf("expected trimmed file contents, got %q", result.String("ENVVALIDATOR_TEST_TOKEN"))
+	}
+}
+
+func TestValidateMap_EnumKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "LOG_LEVEL", Kind: envvalidator.KindEnum, AllowedValues: []string{"debug", "info"}, CaseInsensitiveAllowed: true},
+		envvalidator.Field{Key: "MODE", Kind: envvalidator.KindEnum, AllowedValues: []string{"dev", "prod"}, Default: "prod"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"LOG_LEVEL": "DEBUG"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Enum("LOG_LEVEL"); got != "debug" {
+		t.Errorf("expected canonical value debug, got %q", got)
+	}
+	if got := result.Enum("MODE"); got != "prod" {
+		t.Errorf("expected default prod, got %q", got)
+	}
+	if result.Kind("MODE") != envvalidator.KindEnum {
+		t.Errorf("expected kind enum, got %q", result.Kind("MODE"))
+	}
+
+	_, err = v.ValidateMap(context.Background(), map[string]string{"LOG_LEVEL": "trace"})
+	if !errors.Is(err, envvalidator.ErrNotAllowed) {
+		t.Errorf("expected ErrNotAllowed, got %v", err)
+	}
+}
+
+func TestValidateMap_EnumWithoutAllowedValues(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "MODE", Kind: envvalidator.KindEnum})
+	_, err := v.ValidateMap(context.Background(), map[string]string{"MODE": "dev"})
+	if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), "no AllowedValues") {
+		t.Errorf("expected error about missing AllowedValues, got %v", err)
+	}
+
+	_, err = envvalidator.NewStrict(envvalidator.Field{Key: "MODE", Kind: envvalidator.KindEnum})
+	if err == nil || !strings.Contains(err.Error(), "KindEnum requires AllowedValues") {
+		t.Errorf("expected NewStrict error, got %v", err)
+	}
+}
+
+func TestResult_All(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Default: "4"},
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "2m"},
+		envvalidator.Field{Key: "TOKEN", Secret: true, Required: true},
+	)
+	v.CaptureAll = true
+	result, err := v.ValidateMap(context.Background(), map[string]string{"TOKEN": "s3cret", "EXTRA": "x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	all := result.All()
+	want := map[string]any{"WORKERS": int64(4), "TIMEOUT": 2 * time.Minute, "TOKEN": "s3cret"}
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("expected %v, got %v", want, all)
+	}
+	all["WORKERS"] = int64(99)
+	if result.Integer("WORKERS") != 4 {
+		t.Error("modifying All result affected the Result")
+	}
+}
+
+func TestValidateMap_ExpandDefault(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "CACHE_DIR", Default: "${HOME}/cache", ExpandDefault: true},
+		envvalidator.Field{Key: "GREETING", Default: "hello $USER"},
+		envvalidator.Field{Key: "SPOOL_DIR", Default: "$SPOOL_ROOT/spool", ExpandDefault: true},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"HOME": "/home/app", "USER": "app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.String("CACHE_DIR"); got != "/home/app/cache" {
+		t.Errorf("expected expanded default, got %q", got)
+	}
+	if got := result.String("GREETING"); got != "hello $USER" {
+		t.Errorf("expected literal default without ExpandDefault, got %q", got)
+	}
+	if got := result.String("SPOOL_DIR"); got != "${SPOOL_ROOT}/spool" {
+		t.Errorf("expected unresolved reference kept literally, got %q", got)
+	}
+
+	result, err = v.ValidateMap(context.Background(), map[string]string{"CACHE_DIR": "/tmp/cache"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.String("CACHE_DIR"); got != "/tmp/cache" {
+		t.Errorf("expected provided value to win, got %q", got)
+	}
+}
+
+func TestValidateMap_StrictExpand(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "CACHE_DIR", Default: "${XDG_CACHE_HOME}/app", ExpandDefault: true, StrictExpand: true})
+	_, err := v.ValidateMap(context.Background(), map[string]string{})
+	if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), "unset variables: XDG_CACHE_HOME") {
+		t.Errorf("expected unset reference error, got %v", err)
+	}
+}
+
+func TestValidate_ExpandDefaultFromProcessEnv(t *testing.T) {
+	t.Setenv("EXPAND_TEST_ROOT", "/srv")
+	v := envvalidator.New(envvalidator.Field{Key: "EXPAND_TEST_DIR", Default: "${EXPAND_TEST_ROOT}/data", ExpandDefault: true})
+	result, err := v.Validate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.String("EXPAND_TEST_DIR"); got != "/srv/data" {
+		t.Errorf("expected /srv/data, got %q", got)
+	}
+}
+
+func TestValidateMap_DeprecatedKeys(t *testing.T) {
+	var got []string
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080", DeprecatedKeys: []string{"OLD_PORT", "LEGACY_PORT"}},
+	)
+	v.OnWarning = func(key, msg string) {
+		got = append(got, key+": "+msg)
+	}
+	result, err := v.ValidateMap(context.Background(), map[string]string{"APP_LEGACY_PORT": "9090"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("PORT") != 9090 || result.Source("PORT") != "APP_LEGACY_PORT" {
+		t.Errorf("expected value from APP_LEGACY_PORT, got %d from %q", result.Port("PORT"), result.Source("PORT"))
+	}
+	want := "APP_LEGACY_PORT is deprecated; use APP_PORT instead"
+	if len(got) != 1 || got[0] != "APP_LEGACY_PORT: "+want {
+		t.Errorf("unexpected OnWarning calls: %v", got)
+	}
+
+	got = nil
+	result, err = v.ValidateMap(context.Background(), map[string]string{"APP_PORT": "7070", "APP_OLD_PORT": "9090"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("PORT") != 7070 || len(got) != 0 {
+		t.Errorf("expected the new key to win without warnings, got %d and %v", result.Port("PORT"), got)
+	}
+}
+
+func TestValidateFast(t *testing.T) {
+	var validated []string
+	record := func(raw string) error {
+		validated = append(validated, raw)
+		return nil
+	}
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Required: true},
+		envvalidator.Field{Key: "REGION", Default: "us-east-1", Validate: record},
+	)
+	_, err := v.ValidateFast(context.Background(), map[string]string{"WORKERS": "many"})
+	var verr *envvalidator.ValidationError
+	if !errors.As(err, &verr) || verr.Key != "DATABASE_URL" || !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Fatalf("expected only the DATABASE_URL error, got %v", err)
+	}
+	if _, isList := err.(envvalidator.ValidationErrors); isList {
+		t.Error("expected a single *ValidationError, not ValidationErrors")
+	}
+	if len(validated) != 0 {
+		t.Errorf("expected later fields not to be validated, got %v", validated)
+	}
+
+	result, err := v.ValidateFast(context.Background(), map[string]string{"DATABASE_URL": "postgres://db", "WORKERS": "4"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Integer("WORKERS") != 4 || result.String("REGION") != "us-east-1" {
+		t.Errorf("unexpected result: %v", result.All())
+	}
+}
+
+type testLogLevel string
+
+const (
+	testLevelDebug testLogLevel = "debug"
+	testLevelInfo  testLogLevel = "info"
+)
+
+func TestAllowedFrom(t *testing.T) {
+	allowed := envvalidator.AllowedFrom(testLevelDebug, testLevelInfo)
+	if !reflect.DeepEqual(allowed, []string{"debug", "info"}) {
+		t.Fatalf("unexpected allowed values: %v", allowed)
+	}
+	v := envvalidator.New(envvalidator.Field{Key: "LOG_LEVEL", Kind: envvalidator.KindEnum, AllowedValues: allowed})
+	result, err := v.ValidateMap(context.Background(), map[string]string{"LOG_LEVEL": "info"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if testLogLevel(result.Enum("LOG_LEVEL")) != testLevelInfo {
+		t.Errorf("expected info, got %q", result.Enum("LOG_LEVEL"))
+	}
+}
+
+func TestValidateWith(t *testing.T) {
+	var requested []string
+	fake := map[string]string{"APP_DATABASE_URL": "postgres://localhost/test", "OLD_WORKERS": "3"}
+	getenv := func(key string) string {
+		requested = append(requested, key)
+		return fake[key]
+	}
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Fallbacks: []string{"OLD_WORKERS"}},
+	)
+	result, err := v.ValidateWith(context.Background(), getenv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("DATABASE_URL") != "postgres://localhost/test" || result.Integer("WORKERS") != 3 {
+		t.Errorf("unexpected result: %v", result.All())
+	}
+	want := []string{"APP_DATABASE_URL", "APP_WORKERS", "OLD_WORKERS"}
+	if !reflect.DeepEqual(requested, want) {
+		t.Errorf("expected lookups %v, got %v", want, requested)
+	}
+}
+
+func TestValidateMap_AllowEmpty(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "BANNER", Required: true, AllowEmpty: true},
+		envvalidator.Field{Key: "SUFFIX", Default: "-prod", AllowEmpty: true},
+		envvalidator.Field{Key: "TAG", Default: "latest"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"BANNER": "", "SUFFIX": "", "TAG": ""})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("BANNER") != "" || result.Source("BANNER") != "BANNER" {
+		t.Errorf("expected explicitly empty BANNER to be accepted, got %q from %q", result.String("BANNER"), result.Source("BANNER"))
+	}
+	if result.String("SUFFIX") != "" {
+		t.Errorf("expected explicit empty value to override Default, got %q", result.String("SUFFIX"))
+	}
+	if result.String("TAG") != "latest" {
+		t.Errorf("expected empty TAG without AllowEmpty to use Default, got %q", result.String("TAG"))
+	}
+
+	_, err = v.ValidateMap(context.Background(), map[string]string{})
+	if !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Errorf("expected unset BANNER to fail Required, got %v", err)
+	}
+}
+
+func TestValidate_AllowEmptyUsesLookupEnv(t *testing.T) {
+	t.Setenv("ALLOW_EMPTY_TEST_BANNER", "")
+	v := envvalidator.New(envvalidator.Field{Key: "ALLOW_EMPTY_TEST_BANNER", Required: true, AllowEmpty: true})
+	if _, err := v.Validate(context.Background()); err != nil {
+		t.Errorf("expected set-but-empty variable to be accepted, got %v", err)
+	}
+}
+
+func TestValidationErrors_LogValue(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "API_TOKEN", Kind: envvalidator.KindUUID, Secret: true},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"API_TOKEN": "hunter2"})
+	var buf strings.Builder
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Error("invalid configuration", "errors", err)
+
+	var entry struct {
+		Errors map[string]map[string]string `json:"errors"`
+	}
+	if jerr := json.Unmarshal([]byte(buf.String()), &entry); jerr != nil {
+		t.Fatalf("cannot decode log line %q: %v", buf.String(), jerr)
+	}
+	db := entry.Errors["DATABASE_URL"]
+	if db["reason"] != "required variable is missing or empty" || db["kind"] != "url" || db["value"] != "" {
+		t.Errorf("unexpected DATABASE_URL attributes: %v", db)
+	}
+	token := entry.Errors["API_TOKEN"]
+	if token["value"] != "<redacted>" || strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("expected secret to stay masked, got %s", buf.String())
+	}
+}
+
+func TestValidateMap_MapKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "LABELS", Kind: envvalidator.KindMap, Required: true, DropEmpty: true},
+		envvalidator.Field{Key: "TIMEOUTS", Kind: envvalidator.KindMap, ElementKind: envvalidator.KindDuration, Delimiter: ",", KeyValueSeparator: ":", Default: "read:5s,write:10s"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"LABELS": "env=prod; team = payments;"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Map("LABELS"); !reflect.DeepEqual(got, map[string]string{"env": "prod", "team": "payments"}) {
+		t.Errorf("unexpected labels: %v", got)
+	}
+	timeouts, err := envvalidator.Get[map[string]time.Duration](result, "TIMEOUTS")
+	if err != nil || timeouts["read"] != 5*time.Second || timeouts["write"] != 10*time.Second {
+		t.Errorf("unexpected default timeouts: %v, %v", timeouts, err)
+	}
+	data, _ := json.Marshal(result)
+	if !strings.Contains(string(data), `"TIMEOUTS":{"read":"5s","write":"10s"}`) {
+		t.Errorf("expected durations rendered as strings, got %s", data)
+	}
+}
+
+func TestValidateMap_InvalidMap(t *testing.T) {
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"env=prod;team", `entry 1 "team" is missing the "=" separator`},
+		{"env=prod;=payments", "entry 1 has an empty key"},
+		{"env=prod;env=dev", `entry 1 repeats key "env"`},
+		{"env=prod;;team=a", "entry 1 is empty"},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "LABELS", Kind: envvalidator.KindMap})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"LABELS": tc.input})
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("input %q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+
+	v := envvalidator.New(envvalidator.Field{Key: "LIMITS", Kind: envvalidator.KindMap, ElementKind: envvalidator.KindInteger})
+	_, err := v.ValidateMap(context.Background(), map[string]string{"LIMITS": "cpu=2;memory=lots"})
+	if err == nil || !strings.Contains(err.Error(), `entry "memory": cannot parse "lots" as an integer`) {
+		t.Errorf("expected element parse error, got %v", err)
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "SENTRY_DSN", RequiredIn: []string{"staging", "prod"}},
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "REGION", Default: "us-east-1", RequiredIn: []string{"prod"}},
+	)
+	env := map[string]string{"DATABASE_URL": "postgres://db"}
+
+	if _, err := v.ValidateProfile(context.Background(), env, "dev"); err != nil {
+		t.Errorf("expected SENTRY_DSN to be optional in dev, got %v", err)
+	}
+	_, err := v.ValidateProfile(context.Background(), env, "prod")
+	var errs envvalidator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	e := errs.ByKey("SENTRY_DSN")
+	if e == nil || !errors.Is(e, envvalidator.ErrRequiredMissing) || e.Reason != `required in profile "prod" but is missing or empty` {
+		t.Errorf("unexpected SENTRY_DSN error: %v", e)
+	}
+	if errs.ByKey("REGION") != nil {
+		t.Errorf("expected REGION's Default to satisfy RequiredIn, got %v", errs.ByKey("REGION"))
+	}
+
+	if _, err := v.ValidateProfile(context.Background(), map[string]string{}, "dev"); !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Errorf("expected static Required to apply in every profile, got %v", err)
+	}
+}
+
+func TestResult_Has(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger},
+	)
+	result, errs := v.ValidatePartial(context.Background(), map[string]string{"WORKERS": "many"})
+	if len(errs) != 1 {
+		t.Fatalf("expected one error, got %v", errs)
+	}
+	if !result.Has("PORT") {
+		t.Error("expected PORT to be present")
+	}
+	if result.Has("WORKERS") {
+		t.Error("expected failed WORKERS to be absent")
+	}
+	if result.Has("UNDECLARED") {
+		t.Error("expected undeclared key to be absent")
+	}
+}
+
+func TestValidateParallel(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	slowCheck := func(ctx context.Context, raw string) error {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if raw == "bad" {
+			return errors.New("rejected by remote check")
+		}
+		return nil
+	}
+	var fields []envvalidator.Field
+	env := map[string]string{}
+	for i := 0; i < 8; i++ {
+		key := fmt.Sprintf("ENDPOINT_%d", i)
+		fields = append(fields, envvalidator.Field{Key: key, ValidateCtx: slowCheck})
+		env[key] = "ok"
+	}
+	env["ENDPOINT_6"], env["ENDPOINT_1"] = "bad", "bad"
+	v := envvalidator.New(fields...)
+
+	_, err := v.ValidateParallel(context.Background(), env, 4)
+	var errs envvalidator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	if keys := errs.Keys(); !reflect.DeepEqual(keys, []string{"ENDPOINT_1", "ENDPOINT_6"}) {
+		t.Errorf("expected errors in declaration order, got %v", keys)
+	}
+	if peak < 2 || peak > 4 {
+		t.Errorf("expected between 2 and 4 concurrent hooks, got %d", peak)
+	}
+
+	env["ENDPOINT_6"], env["ENDPOINT_1"] = "ok", "ok"
+	result, err := v.ValidateParallel(context.Background(), env, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.All()) != 8 {
+		t.Errorf("expected 8 values, got %v", result.All())
+	}
+}
+
+func TestValidateParallel_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	v := envvalidator.New(envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"})
+	if _, err := v.ValidateParallel(ctx, map[string]string{}, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestResult_WriteShellExports(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "2m"},
+		envvalidator.Field{Key: "GREETING", Default: "it's here"},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean, Default: "YES"},
+		envvalidator.Field{Key: "TOKEN", Secret: true, Required: true},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"APP_TOKEN": "s3cret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var b strings.Builder
+	if err := result.WriteShellExports(&b, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `export APP_TIMEOUT='2m0s'
+export APP_GREETING='it'\''s here'
+export APP_DEBUG='true'
+export APP_TOKEN='<redacted>'
+`
+	if b.String() != expected {
+		t.Errorf("unexpected exports:\n%s\nwant:\n%s", b.String(), expected)
+	}
+
+	b.Reset()
+	if err := result.WriteShellExports(&b, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(b.String(), "export APP_TOKEN='s3cret'\n") {
+		t.Errorf("expected secret to be included, got:\n%s", b.String())
+	}
+}
+
+func TestValidateMap_PercentageKind(t *testing.T) {
+	cases := []struct {
+		input    string
+		fraction bool
+		want     float64
+	}{
+		{"85", false, 85},
+		{"85%", false, 85},
+		{" 12.5 % ", false, 12.5},
+		{"0", false, 0},
+		{"100%", true, 1},
+		{"85", true, 0.85},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "CPU_THRESHOLD", Kind: envvalidator.KindPercentage, PercentAsFraction: tc.fraction})
+		result, err := v.ValidateMap(context.Background(), map[string]string{"CPU_THRESHOLD": tc.input})
+		if err != nil {
+			t.Errorf("input %q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got := result.Percent("CPU_THRESHOLD"); got != tc.want {
+			t.Errorf("input %q: expected %g, got %g", tc.input, tc.want, got)
+		}
+	}
+
+	v := envvalidator.New(envvalidator.Field{Key: "CPU_THRESHOLD", Kind: envvalidator.KindPercentage, Default: "80%"})
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Percent("CPU_THRESHOLD") != 80 {
+		t.Errorf("expected default 80, got %g", result.Percent("CPU_THRESHOLD"))
+	}
+}
+
+func TestValidateMap_InvalidPercentage(t *testing.T) {
+	cases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"101", `percentage "101" is outside the range 0 to 100`},
+		{"-1%", `percentage "-1%" is outside the range 0 to 100`},
+		{"lots", `cannot parse "lots" as a percentage`},
+		{"NaN%", `cannot parse "NaN%" as a percentage`},
+		{"%", `cannot parse "%" as a percentage`},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "CPU_THRESHOLD", Kind: envvalidator.KindPercentage})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"CPU_THRESHOLD": tc.input})
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("input %q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestResult_DefaultedKeys(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "30s"},
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Default: "8080"},
+		envvalidator.Field{Key: "REGION", Default: "us-east-1"},
+		envvalidator.Field{Key: "NOTE"},
+		envvalidator.Field{Key: "LOG_FORMAT", DefaultFunc: func(map[string]string) string { return "json" }},
+		envvalidator.Field{Key: "LOG_LEVEL", DefaultFunc: func(map[string]string) string { return "" }},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"REGION": "eu-west-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.DefaultedKeys(); !reflect.DeepEqual(got, []string{"TIMEOUT", "PORT", "LOG_FORMAT"}) {
+		t.Errorf("expected [TIMEOUT PORT LOG_FORMAT], got %v", got)
+	}
+}
+
+func TestValidationErrors_Format(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "API_KEY", Kind: envvalidator.KindInteger, Secret: true},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"API_KEY": "hunter2"})
+	var errs envvalidator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	if got := errs.Format(envvalidator.PlainFormatter); got != errs.Error() {
+		t.Errorf("expected PlainFormatter to match Error, got %q", got)
+	}
+	compact := `env-validator: 2 errors: DATABASE_URL: required variable is missing or empty; API_KEY: cannot parse <redacted> as an integer`
+	if got := errs.Format(envvalidator.CompactFormatter); got != compact {
+		t.Errorf("unexpected compact output:\n%s\nwant:\n%s", got, compact)
+	}
+	wantJSON := `[{"key":"DATABASE_URL","reason":"required variable is missing or empty","kind":"url"},` +
+		`{"key":"API_KEY","reason":"cannot parse <redacted> as an integer","kind":"integer","value":"<redacted>"}]`
+	if got := errs.Format(envvalidator.JSONFormatter); got != wantJSON {
+		t.Errorf("unexpected JSON output:\n%s\nwant:\n%s", got, wantJSON)
+	}
+
+	custom := errs.Format(func(ve envvalidator.ValidationErrors) string {
+		return strings.Join(ve.Keys(), ",")
+	})
+	if custom != "DATABASE_URL,API_KEY" {
+		t.Errorf("unexpected custom output: %q", custom)
+	}
+}
+
+func TestValidateMap_RegexKind(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "PATH_FILTER", Kind: envvalidator.KindRegex, Default: `^/api/`})
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if re := result.Regexp("PATH_FILTER"); !re.MatchString("/api/users") || re.MatchString("/static/app.js") {
+		t.Errorf("unexpected default regexp %v", re)
+	}
+	result, err = v.ValidateMap(context.Background(), map[string]string{"PATH_FILTER": `^/v[0-9]+/`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Regexp("PATH_FILTER").MatchString("/v2/items") {
+		t.Error("expected provided regexp to match /v2/items")
+	}
+	data, _ := json.Marshal(result)
+	if string(data) != `{"PATH_FILTER":"^/v[0-9]+/"}` {
+		t.Errorf("expected regexp encoded as its source, got %s", data)
+	}
+}
+
+func TestValidateMap_InvalidRegex(t *testing.T) {
+	v := envvalidator.New(envvalidator.Field{Key: "PATH_FILTER", Kind: envvalidator.KindRegex})
+	_, err := v.ValidateMap(context.Background(), map[string]string{"PATH_FILTER": "[a-z"})
+	if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), "missing closing ]") {
+		t.Errorf("expected compile error text, got %v", err)
+	}
+
+	v = envvalidator.New(envvalidator.Field{Key: "PATH_FILTER", Kind: envvalidator.KindRegex, Secret: true})
+	_, err = v.ValidateMap(context.Background(), map[string]string{"PATH_FILTER": "[secret"})
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected secret pattern to stay hidden, got %v", err)
+	}
+}
+
+func TestValidator_CloneAndWith(t *testing.T) {
+	base := envvalidator.New(
+		envvalidator.Field{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "LOG_LEVEL", AllowedValues: []string{"debug", "info"}, Default: "info"},
+	).RequireAnyOf("db", "DATABASE_URL")
+	base.TrimSpace = true
+
+	clone := base.Clone()
+	clone.TrimSpace = false
+	if !base.TrimSpace {
+		t.Error("changing the clone's options affected the original")
+	}
+
+	api := base.With(envvalidator.Field{Key: "API_PORT", Pattern: `^80`})
+	worker := base.With(envvalidator.Field{Key: "QUEUE", Required: true})
+	if len(base.Fields()) != 2 || len(api.Fields()) != 3 || len(worker.Fields()) != 3 {
+		t.Fatalf("unexpected field counts: base %d, api %d, worker %d", len(base.Fields()), len(api.Fields()), len(worker.Fields()))
+	}
+	if api.Fields()[2].Key != "API_PORT" || worker.Fields()[2].Key != "QUEUE" {
+		t.Errorf("expected each variant to keep its own field, got %s and %s", api.Fields()[2].Key, worker.Fields()[2].Key)
+	}
+
+	result, err := api.ValidateMap(context.Background(), map[string]string{"DATABASE_URL": " postgres://db ", "API_PORT": "8080"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("DATABASE_URL") != "postgres://db" || result.String("API_PORT") != "8080" {
+		t.Errorf("unexpected result: %v", result.All())
+	}
+	if _, err := api.ValidateMap(context.Background(), map[string]string{"DATABASE_URL": "postgres://db", "API_PORT": "9090"}); err == nil {
+		t.Error("expected the added field's Pattern to be enforced")
+	}
+	if _, err := worker.ValidateMap(context.Background(), map[string]string{}); err == nil || !strings.Contains(err.Error(), `group "db"`) {
+		t.Errorf("expected groups to be carried over, got %v", err)
+	}
+}
+
+func TestValidateMap_DefaultFunc(t *testing.T) {
+	logFormat := func(env map[string]string) string {
+		if env["ENV"] == "prod" {
+			return "json"
+		}
+		return ""
+	}
+	v := envvalidator.New(
+		envvalidator.Field{Key: "ENV", Default: "dev"},
+		envvalidator.Field{Key: "LOG_FORMAT", AllowedValues: []string{"json", "text"}, Default: "text", DefaultFunc: logFormat},
+		envvalidator.Field{Key: "AUDIT_SINK", Required: true, DefaultFunc: func(map[string]string) string { return "stdout" }},
+	)
+	cases := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"ENV": "prod"}, "json"},
+		{map[string]string{"ENV": "staging"}, "text"},
+		{map[string]string{"ENV": "prod", "LOG_FORMAT": "text"}, "text"},
+	}
+	for _, tc := range cases {
+		result, err := v.ValidateMap(context.Background(), tc.env)
+		if err != nil {
+			t.Fatalf("env %v: unexpected error: %v", tc.env, err)
+		}
+		if got := result.String("LOG_FORMAT"); got != tc.want {
+			t.Errorf("env %v: expected %q, got %q", tc.env, tc.want, got)
+		}
+		if result.String("AUDIT_SINK") != "stdout" {
+			t.Errorf("env %v: expected DefaultFunc to satisfy Required, got %q", tc.env, result.String("AUDIT_SINK"))
+		}
+	}
+}
+
+func TestValidateMap_CronKind(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "BACKUP_SCHEDULE", Kind: envvalidator.KindCron, Required: true},
+		envvalidator.Field{Key: "REPORT_SCHEDULE", Kind: envvalidator.KindCron, Default: "0 9 * jan-mar MON-FRI"},
+		envvalidator.Field{Key: "POLL_SCHEDULE", Kind: envvalidator.KindCron, CronSeconds: true, Default: "*/15 * * * * *"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{"BACKUP_SCHEDULE": " 0  2,14 */10 * 7 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	backup := result.Cron("BACKUP_SCHEDULE")
+	if backup.String() != "0 2,14 */10 * 7" {
+		t.Errorf("unexpected expression %q", backup.String())
+	}
+	if !reflect.DeepEqual(backup.Hours, []int{2, 14}) || !reflect.DeepEqual(backup.DaysOfMonth, []int{1, 11, 21, 31}) ||
+		!reflect.DeepEqual(backup.DaysOfWeek, []int{0}) || len(backup.Months) != 12 || backup.Seconds != nil {
+		t.Errorf("unexpected schedule: %+v", backup)
+	}
+	report := result.Cron("REPORT_SCHEDULE")
+	if !reflect.DeepEqual(report.Months, []int{1, 2, 3}) || !reflect.DeepEqual(report.DaysOfWeek, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("unexpected named schedule: %+v", report)
+	}
+	if poll := result.Cron("POLL_SCHEDULE"); !reflect.DeepEqual(poll.Seconds, []int{0, 15, 30, 45}) {
+		t.Errorf("unexpected seconds: %v", poll.Seconds)
+	}
+}
+
+func TestValidateMap_InvalidCron(t *testing.T) {
+	cases := []struct {
+		input   string
+		seconds bool
+		wantErr string
+	}{
+		{"0 2 * *", false, "expected 5 fields, got 4"},
+		{"0 0 2 * * *", false, "set CronSeconds to allow a seconds field"},
+		{"0 0 2 * * * *", true, "expected 5 or 6 fields, got 7"},
+		{"0 25 * * *", false, "field 2 (hour): value 25 is out of range 0-23"},
+		{"0 2 0 * *", false, "field 3 (day of month): value 0 is out of range 1-31"},
+		{"*/0 * * * *", false, "field 1 (minute): step must be a positive integer"},
+		{"0 2 * 5-3 *", false, "field 4 (month): range 5-3 is reversed"},
+		{"0 2 * * funday", false, "field 5 (day of week): expected a number, a range, or *"},
+		{"60 * * * * *", true, "field 1 (second): value 60 is out of range 0-59"},
+	}
+	for _, tc := range cases {
+		v := envvalidator.New(envvalidator.Field{Key: "BACKUP_SCHEDULE", Kind: envvalidator.KindCron, CronSeconds: tc.seconds})
+		_, err := v.ValidateMap(context.Background(), map[string]string{"BACKUP_SCHEDULE": tc.input})
+		if !errors.Is(err, envvalidator.ErrInvalidValue) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("input %q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestValidateMap_TreatBlankAsEmpty(t *testing.T) {
+	fields := []envvalidator.Field{
+		{Key: "DATABASE_URL", Kind: envvalidator.KindURL, Required: true},
+		{Key: "REGION", Default: "us-east-1", Fallbacks: []string{"AWS_REGION"}},
+		{Key: "PREFIX", Default: "app"},
+	}
+	env := map[string]string{"DATABASE_URL": "   ", "REGION": "\t", "AWS_REGION": "eu-west-1", "PREFIX": "  x"}
+
+	v := envvalidator.New(fields...)
+	if _, err := v.ValidateMap(context.Background(), env); errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Errorf("expected blank value to count as present by default, got %v", err)
+	}
+
+	v.TreatBlankAsEmpty = true
+	_, err := v.ValidateMap(context.Background(), env)
+	if !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Errorf("expected blank DATABASE_URL to be missing, got %v", err)
+	}
+	env["DATABASE_URL"] = "postgres://db"
+	result, err := v.ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String("REGION") != "eu-west-1" {
+		t.Errorf("expected blank REGION to fall back to AWS_REGION, got %q", result.String("REGION"))
+	}
+	if result.String("PREFIX") != "  x" {
+		t.Errorf("expected non-blank value to keep its whitespace, got %q", result.String("PREFIX"))
+	}
+}
+
+func TestValidationErrors_JSON(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger},
+		envvalidator.Field{Key: "API_KEY", Kind: envvalidator.KindUUID, Secret: true},
+	)
+	_, err := v.ValidateMap(context.Background(), map[string]string{"WORKERS": "many", "API_KEY": "hunter2"})
+	var errs envvalidator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	data, jerr := errs.JSON()
+	if jerr != nil {
+		t.Fatalf("unexpected error: %v", jerr)
+	}
+	var decoded []map[string]string
+	if jerr := json.Unmarshal(data, &decoded); jerr != nil {
+		t.Fatalf("invalid JSON %s: %v", data, jerr)
+	}
+	if len(decoded) != 2 || decoded[0]["key"] != "WORKERS" || decoded[0]["kind"] != "integer" || decoded[0]["value"] != "many" {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+	if decoded[1]["value"] != "<redacted>" || strings.Contains(string(data), "hunter2") {
+		t.Errorf("expected secret to be masked, got %s", data)
+	}
+
+	empty, _ := envvalidator.ValidationErrors(nil).JSON()
+	if string(empty) != "[]" {
+		t.Errorf("expected [] for no errors, got %s", empty)
+	}
+}
+
+func TestResult_Int(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Default: "8"},
+		envvalidator.Field{Key: "NAME", Default: "api"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Int("WORKERS"); got != 8 {
+		t.Errorf("expected 8, got %d", got)
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "is not an integer field") {
+			t.Errorf("expected kind mismatch panic, got %v", r)
+		}
+	}()
+	result.Int("NAME")
+}
+
+func TestValidateMap_AllowedValuesFunc(t *testing.T) {
+	calls := 0
+	regions := func(ctx context.Context) ([]string, error) {
+		calls++
+		return []string{"us-east-1", "eu-west-1"}, nil
+	}
+	v := envvalidator.New(envvalidator.Field{Key: "REGION", Kind: envvalidator.KindEnum, AllowedValuesFunc: regions})
+	result, err := v.ValidateMap(context.Background(), map[string]string{"REGION": "eu-west-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Enum("REGION") != "eu-west-1" || calls != 1 {
+		t.Errorf("expected eu-west-1 after one call, got %q after %d", result.Enum("REGION"), calls)
+	}
+	_, err = v.ValidateMap(context.Background(), map[string]string{"REGION": "ap-south-1"})
+	if !errors.Is(err, envvalidator.ErrNotAllowed) || !strings.Contains(err.Error(), "us-east-1, eu-west-1") {
+		t.Errorf("expected ErrNotAllowed listing the loaded values, got %v", err)
+	}
+
+	unavailable := errors.New("config service unavailable")
+	v = envvalidator.New(envvalidator.Field{Key: "REGION", AllowedValuesFunc: func(context.Context) ([]string, error) {
+		return nil, unavailable
+	}})
+	_, err = v.ValidateMap(context.Background(), map[string]string{"REGION": "eu-west-1"})
+	if !errors.Is(err, unavailable) || !errors.Is(err, envvalidator.ErrInvalidValue) {
+		t.Errorf("expected the loader error to be wrapped, got %v", err)
+	}
+}
+
+func TestValidateMap_AllowedValuesFuncCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	v := envvalidator.New(envvalidator.Field{Key: "REGION", AllowedValuesFunc: func(ctx context.Context) ([]string, error) {
+		cancel()
+		return nil, ctx.Err()
+	}})
+	_, err := v.ValidateMap(ctx, map[string]string{"REGION": "eu-west-1"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, isList := err.(envvalidator.ValidationErrors); isList {
+		t.Errorf("expected the bare context error, got %v", err)
+	}
+}
+
+func TestResult_InfoLabels(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "90s"},
+		envvalidator.Field{Key: "DEBUG", Kind: envvalidator.KindBoolean, Default: "YES"},
+		envvalidator.Field{Key: "UPSTREAM.URL", Kind: envvalidator.KindURL, Default: "https://api.example.com"},
+		envvalidator.Field{Key: "API_TOKEN", Secret: true, Default: "s3cret"},
+	)
+	result, err := v.ValidateMap(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"timeout": "1m30s", "debug": "true", "upstream_url": "https://api.example.com"}
+	if got := result.InfoLabels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestValidator_SubAndMerge(t *testing.T) {
+	upstream := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "URL", Kind: envvalidator.KindURL, Required: true},
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "5s"},
+		envvalidator.Field{Key: "TOKEN", Secret: true, DeprecatedKeys: []string{"KEY"}},
+	)
+	env := map[string]string{
+		"APP_UPSTREAM_A_URL":     "https://a.example.com",
+		"APP_UPSTREAM_A_TOKEN":   "token-a",
+		"APP_UPSTREAM_B_URL":     "https://b.example.com",
+		"APP_UPSTREAM_B_TIMEOUT": "2s",
+		"APP_UPSTREAM_B_KEY":     "token-b",
+	}
+	a, err := upstream.Sub("UPSTREAM_A_").ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected error for A: %v", err)
+	}
+	b, err := upstream.Sub("UPSTREAM_B_").ValidateMap(context.Background(), env)
+	if err != nil {
+		t.Fatalf("unexpected error for B: %v", err)
+	}
+	result := a.Merge(b)
+	if result.String("UPSTREAM_A_URL") != "https://a.example.com" || result.String("UPSTREAM_B_URL") != "https://b.example.com" {
+		t.Errorf("unexpected URLs: %v", result.Redacted())
+	}
+	if result.Duration("UPSTREAM_A_TIMEOUT") != 5*time.Second || result.Duration("UPSTREAM_B_TIMEOUT") != 2*time.Second {
+		t.Errorf("unexpected timeouts: %v", result.Redacted())
+	}
+	if result.String("UPSTREAM_B_TOKEN") != "token-b" || result.Redacted()["UPSTREAM_B_TOKEN"] != "<redacted>" {
+		t.Errorf("expected B's token from its deprecated key and still secret, got %v", result.Redacted())
+	}
+	if w := result.Warnings(); len(w) != 1 || !strings.Contains(w[0], "APP_UPSTREAM_B_KEY is deprecated") {
+		t.Errorf("expected merged warnings, got %v", w)
+	}
+	if got := result.DefaultedKeys(); !reflect.DeepEqual(got, []string{"UPSTREAM_A_TIMEOUT"}) {
+		t.Errorf("unexpected defaulted keys: %v", got)
+	}
+	if len(upstream.Fields()) != 3 || upstream.Fields()[0].Key != "URL" {
+		t.Errorf("expected Sub to leave the original untouched, got %v", upstream.Fields()[0].Key)
+	}
+
+	_, err = upstream.Sub("UPSTREAM_C_").ValidateMap(context.Background(), env)
+	var errs envvalidator.ValidationErrors
+	if !errors.As(err, &errs) || errs.ByKey("APP_UPSTREAM_C_URL") == nil {
+		t.Errorf("expected a missing APP_UPSTREAM_C_URL error, got %v", err)
+	}
+}
+
+func TestValidateMap_OnFieldValidated(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Required: true},
+		envvalidator.Field{Key: "SLOW", Validate: func(string) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		}},
+		envvalidator.Field{Key: "MODE", AllowedValues: []string{"a", "b"}},
+	)
+	var keys []string
+	durs := map[string]time.Duration{}
+	errs := map[string]error{}
+	v.OnFieldValidated = func(key string, dur time.Duration, err error) {
+		keys = append(keys, key)
+		durs[key], errs[key] = dur, err
+	}
+	_, err := v.ValidateMap(context.Background(), map[string]string{
+		"APP_PORT": "8080",
+		"APP_SLOW": "x",
+		"APP_MODE": "c",
+	})
+	if err == nil {
+		t.Fatal("expected an error for APP_MODE")
+	}
+	if want := []string{"APP_PORT", "APP_SLOW", "APP_MODE"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("unexpected OnFieldValidated calls: got %v, want %v", keys, want)
+	}
+	if durs["APP_SLOW"] < 5*time.Millisecond {
+		t.Errorf("expected APP_SLOW to take at least 5ms, got %v", durs["APP_SLOW"])
+	}
+	if errs["APP_PORT"] != nil || errs["APP_SLOW"] != nil {
+		t.Errorf("expected nil errors for valid fields, got %v", errs)
+	}
+	var ve *envvalidator.ValidationError
+	if !errors.As(errs["APP_MODE"], &ve) || ve.Key != "APP_MODE" {
+		t.Errorf("expected a *ValidationError for APP_MODE, got %v", errs["APP_MODE"])
+	}
+}
+
+func TestValidateMap_CaseInsensitiveKeys(t *testing.T) {
+	v := envvalidator.NewWithPrefix("APP_",
+		envvalidator.Field{Key: "PORT", Kind: envvalidator.KindPort, Required: true},
+		envvalidator.Field{Key: "HOST", Fallbacks: []string{"HOSTNAME"}},
+	)
+	v.CaseInsensitiveKeys = true
+	v.CaptureAll = true
+	result, err := v.ValidateMap(context.Background(), map[string]string{
+		"app_port": "8080",
+		"HostName": "example.com",
+		"Other":    "kept",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Port("PORT") != 8080 || result.String("HOST") != "example.com" {
+		t.Errorf("unexpected values: %v", result.Redacted())
+	}
+	if src := result.Source("HOST"); src != "HOSTNAME" {
+		t.Errorf("expected HOST to come from HOSTNAME, got %q", src)
+	}
+	if got := result.Undeclared(); !reflect.DeepEqual(got, map[string]string{"Other": "kept"}) {
+		t.Errorf("unexpected undeclared variables: %v", got)
+	}
+
+	_, err = v.ValidateMap(context.Background(), map[string]string{
+		"APP_PORT": "8080",
+		"app_port": "9090",
+	})
+	var errs envvalidator.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(err, envvalidator.ErrConflict) {
+		t.Fatalf("expected one ErrConflict error, got %v", err)
+	}
+	if errs[0].Key != "APP_PORT" || !strings.Contains(errs[0].Reason, "APP_PORT, app_port") {
+		t.Errorf("unexpected conflict error: %v", errs[0])
+	}
+
+	v.CaseInsensitiveKeys = false
+	if _, err := v.ValidateMap(context.Background(), map[string]string{"app_port": "8080"}); !errors.Is(err, envvalidator.ErrRequiredMissing) {
+		t.Errorf("expected exact matching without CaseInsensitiveKeys, got %v", err)
+	}
+}
+
+func TestValidateMap_WeakValues(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "API_KEY", Secret: true, MinLen: 20, WeakValues: []string{"changeme", "password"}},
+		envvalidator.Field{Key: "ADMIN_PASSWORD", Default: "changeme", WeakValues: []string{"changeme"}},
+	)
+	cases := []struct {
+		name    string
+		env     map[string]string
+		wantKey string
+		wantErr string
+	}{
+		{"strong", map[string]string{"API_KEY": "a-long-random-secret-value", "ADMIN_PASSWORD": "s3cr3t"}, "", ""},
+		{"weak any case", map[string]string{"API_KEY": "PassWord", "ADMIN_PASSWORD": "s3cr3t"}, "API_KEY", "value is too weak (matches a known weak value)"},
+		{"too short", map[string]string{"API_KEY": "short", "ADMIN_PASSWORD": "s3cr3t"}, "API_KEY", "below minimum 20"},
+		{"weak default", map[string]string{"API_KEY": "a-long-random-secret-value"}, "ADMIN_PASSWORD", "value is too weak (matches a known weak value)"},
+	}
+	for _, tc := range cases {
+		_, err := v.ValidateMap(context.Background(), tc.env)
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.name, err)
+			}
+			continue
+		}
+		var errs envvalidator.ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Key != tc.wantKey || !strings.Contains(errs[0].Reason, tc.wantErr) {
+			t.Errorf("%s: expected %s error containing %q, got %v", tc.name, tc.wantKey, tc.wantErr, err)
+			continue
+		}
+		if strings.Contains(err.Error(), "PassWord") || strings.Contains(err.Error(), "changeme") {
+			t.Errorf("%s: error echoes the value: %v", tc.name, err)
+		}
+		if errs[0].Value != "<redacted>" {
+			t.Errorf("%s: expected a masked Value, got %q", tc.name, errs[0].Value)
+		}
+	}
+}
+
+func TestResult_MustEnum(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "LOG_LEVEL", Kind: envvalidator.KindEnum, AllowedValues: []string{"debug", "info"}, Default: "info"},
+		envvalidator.Field{Key: "MODE", Default: "fast"},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Default: "4"},
+	)
+	result, err := v.ValidateMap(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ran []string
+	handler := func(name string) func() { return func() { ran = append(ran, name) } }
+	if err := result.MustEnum("LOG_LEVEL", map[string]func(){"debug": handler("debug"), "info": handler("info")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ran, []string{"info"}) {
+		t.Errorf("expected only the info handler to run, got %v", ran)
+	}
+
+	ran = nil
+	err = result.MustEnum("LOG_LEVEL", map[string]func(){"info": handler("info")})
+	if err == nil || !strings.Contains(err.Error(), "no handler for allowed values: debug") {
+		t.Errorf("expected an unhandled allowed value error, got %v", err)
+	}
+	if ran != nil {
+		t.Errorf("expected no handler to run on error, got %v", ran)
+	}
+
+	if err := result.MustEnum("MODE", map[string]func(){"slow": handler("slow")}); err == nil || !strings.Contains(err.Error(), `value "fast" has no handler`) {
+		t.Errorf("expected a missing handler error, got %v", err)
+	}
+	if err := result.MustEnum("WORKERS", nil); err == nil || !strings.Contains(err.Error(), "not string") {
+		t.Errorf("expected a type error, got %v", err)
+	}
+	if err := result.MustEnum("MISSING", nil); err == nil || !strings.Contains(err.Error(), "not declared") {
+		t.Errorf("expected an undeclared key error, got %v", err)
+	}
+}
+
+func TestResult_ErrorAccessors(t *testing.T) {
+	v := envvalidator.New(
+		envvalidator.Field{Key: "NAME", Default: "svc"},
+		envvalidator.Field{Key: "WORKERS", Kind: envvalidator.KindInteger, Default: "4"},
+		envvalidator.Field{Key: "TIMEOUT", Kind: envvalidator.KindDuration, Default: "2s"},
+		envvalidator.Field{Key: "LEVEL", Kind: envvalidator.KindEnum, AllowedValues: []string{"info"}, Default: "info"},
+		envvalidator.Field{Key: "VERSION", Kind: envvalidator.KindSemver, Default: "1.2.3-rc.1"},
+	)
+	result, err := v.ValidateMap(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		name string
+		get  func() (any, error)
+		want any
+	}{
+		{"StringE", func() (any, error) { return result.StringE("NAME") }, "svc"},
+		{"IntE", func() (any, error) { return result.IntE("WORKERS") }, 4},
+		{"DurationE", func() (any, error) { return result.DurationE("TIMEOUT") }, 2 * time.Second},
+		{"EnumE", func() (any, error) { return result.EnumE("LEVEL") }, "info"},
+		{"SemverE", func() (any, error) { return result.SemverE("VERSION") }, envvalidator.SemanticVersion{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}},
+	}
+	for _, tc := range cases {
+		if got, err := tc.get(); err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, %v; want %v", tc.name, got, err, tc.want)
+		}
+	}
+	if _, err := result.SemverE("NAME"); err == nil || !strings.Contains(err.Error(), "is not a semver field") {
+		t.Errorf("expected a semver kind mismatch error, got %v", err)
+	}
+
+	if _, err := result.IntegerE("NAME"); err == nil || !strings.Contains(err.Error(), `key "NAME" is not an integer field`) {
+		t.Errorf("expected a kind mismatch error, got %v", err)
+	}
+	if _, err := result.EnumE("NAME"); err == nil || !strings.Contains(err.Error(), "is not an enum field") {
+		t.Errorf("expected a kind check error, got %v", err)
+	}
+	if _, err := result.DurationE("MISSING"); err == nil || !strings.Contains(err.Error(), "was not declared") {
+		t.Errorf("expected an undeclared key error, got %v", err)
+	}
+
+	_, wantErr := result.BooleanE("NAME")
+	defer func() {
+		if r := recover(); r == nil || fmt.Sprint(r) != wantErr.Error() {
+			t.Errorf("expected Boolean to panic with %q, got %v", wantErr, r)
+		}
+	}()
+	result.Boolean("NAME")
+}